@@ -0,0 +1,108 @@
+package getblock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// BatchElem is call sent in batch by CallBatch.
+type BatchElem struct {
+	Method string
+	Params []interface{}
+	// Result is pointer result is decoded into, result is not decoded if nil.
+	Result interface{}
+	// Error is JSON-RPC error or result decoding error set by CallBatch.
+	Error error
+}
+
+// CallBatch sends calls as single batch request to one endpoint. Returned error is error of
+// whole batch, errors of calls are set in their Error. Batch is rate limited, charged and retried
+// like a call with weights and costs of all its calls, but it bypasses middlewares, cache and deduplication.
+// Per-call options other than WithCacheBypass apply to batch as a whole.
+func (c *Client) CallBatch(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ctx, cancel, header := callOptionsFromContext(ctx).apply(ctx)
+	defer cancel()
+
+	h := HandlerFunc(func(ctx context.Context, _ *Request) (*Response, error) {
+		if c.limiter != nil {
+			weight := 0
+			for _, elem := range batch {
+				if w := c.weights[elem.Method]; w > 1 {
+					weight += w
+				} else {
+					weight++
+				}
+			}
+
+			if err := c.limiter.wait(ctx, weight); err != nil {
+				return nil, err
+			}
+		}
+
+		err := c.sendBatch(ctx, header, batch)
+		if c.limiter != nil {
+			c.limiter.observe(err)
+		}
+
+		return nil, err
+	})
+
+	_, err := Retry(c.attempts)(h).Handle(contextWithCallInfo(ctx), &Request{})
+
+	return err
+}
+
+// sendBatch sends batch to endpoint chosen according to policy and health and sets results of calls.
+func (c *Client) sendBatch(ctx context.Context, header http.Header, batch []BatchElem) error {
+	first := atomic.AddUint64(&c.ids, uint64(len(batch))) - uint64(len(batch)) + 1
+	requests := make([]request, len(batch))
+	methods := make([]string, len(batch))
+	for i, elem := range batch {
+		requests[i] = newRequest(first+uint64(i), elem.Method, elem.Params)
+		methods[i] = elem.Method
+	}
+
+	data, err := json.Marshal(requests)
+	if err != nil {
+		return fmt.Errorf("getblock: batch: encode request: %w", err)
+	}
+
+	var responses []*Response
+	err = c.roundTrip(ctx, header, methods, func(ctx context.Context, e *endpoint) error {
+		return e.call(ctx, data, &responses)
+	})
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uint64]*Response, len(responses))
+	for _, r := range responses {
+		if r != nil {
+			byID[r.ID] = r
+		}
+	}
+
+	for i := range batch {
+		elem := &batch[i]
+		r, ok := byID[first+uint64(i)]
+		switch {
+		case !ok:
+			elem.Error = fmt.Errorf("getblock: %s: missing response in batch", elem.Method)
+		case r.Error != nil:
+			elem.Error = r.Error
+		case elem.Result != nil:
+			elem.Error = r.Decode(elem.Result)
+		default:
+			elem.Error = nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package getblock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 30 * time.Second
+	defaultBreakerProbes   = 1
+)
+
+// CircuitState is state of endpoint circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests until cooldown elapses.
+	CircuitOpen
+	// CircuitHalfOpen lets single probe request through at a time.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreaker is configuration of circuit breaker of every endpoint. Zero fields take defaults.
+type CircuitBreaker struct {
+	// Failures is number of consecutive transport or 5xx errors opening circuit. Default is 5.
+	Failures int
+	// Cooldown is time circuit stays open before probe request is let through. Default is 30 seconds.
+	Cooldown time.Duration
+	// Probes is number of successful probe requests closing half-open circuit. Default is 1.
+	Probes int
+	// OnStateChange is called when circuit of endpoint with host changes state.
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// WithCircuitBreaker enables circuit breaker per endpoint. Endpoints with open circuit are skipped,
+// if circuits of all endpoints are open call fails with CircuitOpenError without sending request.
+func WithCircuitBreaker(cb CircuitBreaker) Option {
+	return func(c *Client) {
+		if cb.Failures <= 0 {
+			cb.Failures = defaultBreakerFailures
+		}
+		if cb.Cooldown <= 0 {
+			cb.Cooldown = defaultBreakerCooldown
+		}
+		if cb.Probes <= 0 {
+			cb.Probes = defaultBreakerProbes
+		}
+
+		c.breaker = &cb
+	}
+}
+
+// CircuitOpenError is returned when circuits of all endpoints are open.
+type CircuitOpenError struct {
+	// Host is host of endpoint closest to be probed again.
+	Host string
+	// Until is time when circuit of Host lets probe request through.
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("getblock: circuit of %s is open until %s", e.Host, e.Until.Format(time.RFC3339))
+}
+
+// circuit is circuit breaker of single endpoint.
+type circuit struct {
+	cfg  *CircuitBreaker
+	host string
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	successes int
+	openedAt  time.Time
+	probing   bool
+}
+
+// acquire reports whether request may be sent now. If it may not, error tells when circuit lets probe through.
+func (c *circuit) acquire(now time.Time) *CircuitOpenError {
+	c.mu.Lock()
+	from := c.state
+
+	switch {
+	case c.state == CircuitOpen && now.Before(c.openedAt.Add(c.cfg.Cooldown)),
+		c.state == CircuitHalfOpen && c.probing:
+		until := c.openedAt.Add(c.cfg.Cooldown)
+		c.mu.Unlock()
+		if until.Before(now) {
+			until = now
+		}
+
+		return &CircuitOpenError{Host: c.host, Until: until}
+	case c.state == CircuitOpen:
+		c.state = CircuitHalfOpen
+		c.successes = 0
+		fallthrough
+	case c.state == CircuitHalfOpen:
+		c.probing = true
+	}
+
+	to := c.state
+	c.mu.Unlock()
+	c.notify(from, to)
+
+	return nil
+}
+
+// record updates circuit with outcome of request. Canceled requests only release probe.
+func (c *circuit) record(err error, canceled bool) {
+	c.mu.Lock()
+	from := c.state
+	failed := err != nil && isRetryable(err)
+
+	switch c.state {
+	case CircuitClosed:
+		switch {
+		case canceled:
+		case failed:
+			c.failures++
+			if c.failures >= c.cfg.Failures {
+				c.open()
+			}
+		default:
+			c.failures = 0
+		}
+	case CircuitHalfOpen:
+		c.probing = false
+		switch {
+		case canceled:
+		case failed:
+			c.open()
+		default:
+			c.successes++
+			if c.successes >= c.cfg.Probes {
+				c.state = CircuitClosed
+				c.failures = 0
+			}
+		}
+	}
+
+	to := c.state
+	c.mu.Unlock()
+	c.notify(from, to)
+}
+
+func (c *circuit) open() {
+	c.state = CircuitOpen
+	c.openedAt = time.Now()
+	c.probing = false
+}
+
+func (c *circuit) notify(from, to CircuitState) {
+	if from != to && c.cfg.OnStateChange != nil {
+		c.cfg.OnStateChange(c.host, from, to)
+	}
+}
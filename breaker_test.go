@@ -0,0 +1,126 @@
+package getblock_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+
+	type step int
+	const (
+		ok step = iota
+		fail
+		rpcError
+		// open is call rejected by open circuit without request.
+		open
+		// wait waits for cooldown.
+		wait
+	)
+
+	var (
+		closed   = getblock.CircuitClosed
+		opened   = getblock.CircuitOpen
+		halfOpen = getblock.CircuitHalfOpen
+	)
+
+	tests := []struct {
+		name  string
+		steps []step
+		want  [][2]getblock.CircuitState
+	}{
+		{
+			name:  "opens after consecutive failures",
+			steps: []step{fail, fail, open},
+			want:  [][2]getblock.CircuitState{{closed, opened}},
+		},
+		{
+			name:  "success resets failures",
+			steps: []step{fail, ok, fail, ok},
+		},
+		{
+			name:  "JSON-RPC errors are not failures",
+			steps: []step{rpcError, rpcError, rpcError, ok},
+		},
+		{
+			name:  "successful probe closes",
+			steps: []step{fail, fail, open, wait, ok, ok},
+			want:  [][2]getblock.CircuitState{{closed, opened}, {opened, halfOpen}, {halfOpen, closed}},
+		},
+		{
+			name:  "failed probe reopens",
+			steps: []step{fail, fail, wait, fail, open},
+			want:  [][2]getblock.CircuitState{{closed, opened}, {opened, halfOpen}, {halfOpen, opened}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			s.Respond("eth_blockNumber", "0x1")
+
+			var mu sync.Mutex
+			var got [][2]getblock.CircuitState
+			c := s.GetblockClient(getblock.WithRetry(1), getblock.WithCircuitBreaker(getblock.CircuitBreaker{
+				Failures: 2,
+				Cooldown: cooldown,
+				OnStateChange: func(_ string, from, to getblock.CircuitState) {
+					mu.Lock()
+					got = append(got, [2]getblock.CircuitState{from, to})
+					mu.Unlock()
+				},
+			}))
+
+			for i, st := range tt.steps {
+				switch st {
+				case wait:
+					time.Sleep(cooldown)
+					continue
+				case fail:
+					s.Inject(getblocktest.Fault{Times: 1, Status: http.StatusServiceUnavailable})
+				case rpcError:
+					s.Inject(getblocktest.Fault{Times: 1, Error: &getblocktest.Error{Code: getblocktest.CodeServerError, Message: "boom"}})
+				}
+
+				before := len(s.Calls())
+				_, err := c.Call(context.Background(), "eth_blockNumber")
+				sent := len(s.Calls()) > before
+
+				var openErr *getblock.CircuitOpenError
+				switch st {
+				case ok, rpcError:
+					if err != nil {
+						t.Fatalf("step %d: %v", i, err)
+					}
+				case fail:
+					if err == nil {
+						t.Fatalf("step %d: expected error", i)
+					}
+				case open:
+					if !errors.As(err, &openErr) {
+						t.Fatalf("step %d: error = %v, want CircuitOpenError", i, err)
+					}
+					if sent {
+						t.Fatalf("step %d: request sent through open circuit", i)
+					}
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("transitions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package getblock
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// finalizedTTL is how long finalized block number is reused before refreshing.
+const finalizedTTL = 30 * time.Second
+
+// Cache stores serialized responses. Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+}
+
+// WithCache enables caching of immutable responses: chain ID, blocks and their transactions
+// requested by hash, and responses related to finalized blocks (e.g. blocks by number and receipts).
+// Requests referring block tags like "latest" or "pending" are never cached.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// CacheStats is cache hits and misses of Client.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// CacheStats returns cache hits and misses of Client.
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&c.cacheHits),
+		Misses: atomic.LoadInt64(&c.cacheMisses),
+	}
+}
+
+// cacheRule describes when response of method is immutable.
+type cacheRule struct {
+	// always means response is immutable once it is not null.
+	always bool
+	// blockParam is index of block number parameter, response is immutable if block is finalized.
+	blockParam int
+	// blockResult means response is immutable if block number of result is finalized.
+	blockResult bool
+}
+
+var cacheRules = map[string]cacheRule{
+	"eth_chainId":                             {always: true},
+	"net_version":                             {always: true},
+	"eth_getBlockByHash":                      {always: true},
+	"eth_getBlockTransactionCountByHash":      {always: true},
+	"eth_getTransactionByBlockHashAndIndex":   {always: true},
+	"eth_getUncleByBlockHashAndIndex":         {always: true},
+	"eth_getUncleCountByBlockHash":            {always: true},
+	"eth_getBlockByNumber":                    {blockParam: 0},
+	"eth_getBlockTransactionCountByNumber":    {blockParam: 0},
+	"eth_getTransactionByBlockNumberAndIndex": {blockParam: 0},
+	"eth_getUncleByBlockNumberAndIndex":       {blockParam: 0},
+	"eth_getUncleCountByBlockNumber":          {blockParam: 0},
+	"eth_getBalance":                          {blockParam: 1},
+	"eth_getCode":                             {blockParam: 1},
+	"eth_getTransactionCount":                 {blockParam: 1},
+	"eth_call":                                {blockParam: 1},
+	"eth_getStorageAt":                        {blockParam: 2},
+	"eth_getProof":                            {blockParam: 2},
+	"trace_block":                             {blockParam: 0},
+	"trace_replayBlockTransactions":           {blockParam: 0},
+	"debug_traceBlockByNumber":                {blockParam: 0},
+	"eth_getTransactionByHash":                {blockParam: -1, blockResult: true},
+	"eth_getTransactionReceipt":               {blockParam: -1, blockResult: true},
+	"trace_transaction":                       {blockParam: -1, blockResult: true},
+}
+
+// cacheMiddleware serves immutable responses from cache and stores them after call.
+func (c *Client) cacheMiddleware(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+		rule, ok := cacheRules[req.Method]
+		if !ok || hasBlockTag(req.Params) {
+			return next.Handle(ctx, req)
+		}
+
+		key, err := cacheKey(req.Method, req.Params)
+		if err != nil {
+			return next.Handle(ctx, req)
+		}
+
+		if !callOptionsFromContext(ctx).cacheBypass {
+			if data, ok := c.cache.Get(key); ok {
+				r, err := decodeResponse(data)
+				if err == nil {
+					atomic.AddInt64(&c.cacheHits, 1)
+					return r, nil
+				}
+			}
+
+			atomic.AddInt64(&c.cacheMisses, 1)
+		}
+
+		r, err := next.Handle(ctx, req)
+		if err != nil || r == nil || r.Error != nil || isNull(r.Result) {
+			return r, err
+		}
+
+		if !rule.always {
+			n, ok := blockNumberOf(rule, req.Params, r.Result)
+			if !ok || !c.isFinalized(ctx, next, n) {
+				return r, nil
+			}
+		}
+
+		if data, err := json.Marshal(r); err == nil {
+			c.cache.Set(key, data)
+		}
+
+		return r, nil
+	})
+}
+
+// isFinalized reports whether block number n is not greater than finalized block number.
+// Finalized block number is requested with h using context without values of ctx, so the lookup
+// is not accounted as attempt of the call and does not use its per-call options.
+func (c *Client) isFinalized(ctx context.Context, h Handler, n uint64) bool {
+	c.finalizedMu.Lock()
+	if n <= c.finalized {
+		c.finalizedMu.Unlock()
+		return true
+	}
+
+	if time.Since(c.finalizedAt) < finalizedTTL {
+		c.finalizedMu.Unlock()
+		return false
+	}
+
+	c.finalizedAt = time.Now()
+	c.finalizedMu.Unlock()
+
+	r, err := h.Handle(valuelessContext{ctx}, &Request{Method: "eth_getBlockByNumber", Params: []interface{}{"finalized", false}, Header: http.Header{}})
+	if err != nil || r.Error != nil {
+		return false
+	}
+
+	var block struct {
+		Number interface{} `json:"number"`
+	}
+	if err := r.Decode(&block); err != nil {
+		return false
+	}
+
+	finalized, ok := parseBlockNumber(block.Number)
+	if !ok {
+		return false
+	}
+
+	c.finalizedMu.Lock()
+	defer c.finalizedMu.Unlock()
+
+	if finalized > c.finalized {
+		c.finalized = finalized
+	}
+
+	return n <= c.finalized
+}
+
+// valuelessContext is canceled with parent context but has none of its values.
+type valuelessContext struct {
+	context.Context
+}
+
+func (valuelessContext) Value(interface{}) interface{} {
+	return nil
+}
+
+func hasBlockTag(params []interface{}) bool {
+	for _, p := range params {
+		switch p {
+		case "latest", "pending", "safe", "finalized", "earliest":
+			return true
+		}
+	}
+
+	return false
+}
+
+func cacheKey(method string, params []interface{}) (string, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	return method + ":" + string(data), nil
+}
+
+func blockNumberOf(rule cacheRule, params []interface{}, result json.RawMessage) (uint64, bool) {
+	if rule.blockResult {
+		if len(result) > 0 && result[0] == '[' {
+			var list []json.RawMessage
+			if err := json.Unmarshal(result, &list); err != nil || len(list) == 0 {
+				return 0, false
+			}
+			result = list[0]
+		}
+
+		// trace_transaction returns block number as JSON number
+		var v struct {
+			BlockNumber interface{} `json:"blockNumber"`
+		}
+		if err := decodeJSON(result, &v); err != nil {
+			return 0, false
+		}
+
+		return parseBlockNumber(v.BlockNumber)
+	}
+
+	if rule.blockParam >= len(params) {
+		return 0, false
+	}
+
+	return parseBlockNumber(params[rule.blockParam])
+}
+
+// parseBlockNumber parses hex or decimal block number.
+func parseBlockNumber(v interface{}) (uint64, bool) {
+	switch v := v.(type) {
+	case string:
+		if strings.HasPrefix(v, "0x") {
+			n, err := strconv.ParseUint(v[2:], 16, 64)
+			return n, err == nil
+		}
+	case json.Number:
+		n, err := strconv.ParseUint(v.String(), 10, 64)
+		return n, err == nil
+	}
+
+	return 0, false
+}
+
+func decodeResponse(data []byte) (*Response, error) {
+	var r *Response
+	err := json.Unmarshal(data, &r)
+
+	return r, err
+}
+
+// LRUCache is in-memory Cache evicting least recently used entries.
+type LRUCache struct {
+	maxEntries int
+	maxBytes   int64
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+	size  int64
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// NewLRUCache creates LRUCache holding at most maxEntries entries of maxBytes total size.
+// Zero value of limit means no limit.
+func NewLRUCache(maxEntries int, maxBytes int64) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns value stored under key.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(e)
+
+	return e.Value.(*lruEntry).value, true
+}
+
+// Set stores value under key evicting least recently used entries if limits are exceeded.
+func (c *LRUCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lruEntry)
+		c.size += int64(len(value)) - int64(len(entry.value))
+		entry.value = value
+		c.ll.MoveToFront(e)
+	} else {
+		c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value})
+		c.size += int64(len(value))
+	}
+
+	for c.ll.Len() > 0 && (c.maxEntries > 0 && c.ll.Len() > c.maxEntries || c.maxBytes > 0 && c.size > c.maxBytes) {
+		e := c.ll.Back()
+		entry := e.Value.(*lruEntry)
+		c.ll.Remove(e)
+		delete(c.items, entry.key)
+		c.size -= int64(len(entry.value))
+	}
+}
+
+// Len returns number of entries in cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+// Size returns total size of values in cache.
+func (c *LRUCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.size
+}
@@ -0,0 +1,122 @@
+package getblock_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestCache(t *testing.T) {
+	const (
+		alice = "0x00000000000000000000000000000000000a11ce"
+		bob   = "0x0000000000000000000000000000000000000b0b"
+	)
+
+	b := getblocktest.NewBackend(map[string]*big.Int{alice: big.NewInt(1e18)})
+	defer b.Close()
+
+	minedTx, err := b.Transfer(alice, bob, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockHash := b.Commit()
+	b.Commit()
+
+	pendingTx, err := b.Transfer(alice, bob, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		params []interface{}
+		ctx    func(context.Context) context.Context
+		cached bool
+	}{
+		{name: "chain ID", method: "eth_chainId", cached: true},
+		{name: "block by hash", method: "eth_getBlockByHash", params: []interface{}{blockHash, false}, cached: true},
+		{name: "finalized block by number", method: "eth_getBlockByNumber", params: []interface{}{"0x1", true}, cached: true},
+		{name: "block by tag", method: "eth_getBlockByNumber", params: []interface{}{"latest", false}},
+		{name: "unknown block", method: "eth_getBlockByNumber", params: []interface{}{"0x100", false}},
+		{name: "balance at finalized block", method: "eth_getBalance", params: []interface{}{alice, "0x1"}, cached: true},
+		{name: "mined transaction receipt", method: "eth_getTransactionReceipt", params: []interface{}{minedTx}, cached: true},
+		{name: "pending transaction receipt", method: "eth_getTransactionReceipt", params: []interface{}{pendingTx}},
+		{name: "mutable method", method: "eth_blockNumber"},
+		{
+			name:   "bypass",
+			method: "eth_getBlockByHash",
+			params: []interface{}{blockHash, true},
+			ctx:    getblock.WithCacheBypass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := b.GetblockClient(getblock.WithCache(getblock.NewLRUCache(0, 0)))
+
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(ctx)
+			}
+
+			before := countCalls(b.Server, tt.method, tt.params)
+
+			var results []string
+			for i := 0; i < 2; i++ {
+				r, err := c.Call(ctx, tt.method, tt.params...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if r.Error != nil {
+					t.Fatal(r.Error)
+				}
+
+				results = append(results, string(r.Result))
+			}
+
+			if results[0] != results[1] {
+				t.Errorf("cached result %s differs from %s", results[1], results[0])
+			}
+
+			wantCalls := 2
+			if tt.cached {
+				wantCalls = 1
+			}
+
+			if n := countCalls(b.Server, tt.method, tt.params) - before; n != wantCalls {
+				t.Errorf("got %d upstream calls, want %d", n, wantCalls)
+			}
+
+			if stats := c.CacheStats(); tt.cached && stats.Hits != 1 {
+				t.Errorf("cache hits = %d, want 1", stats.Hits)
+			}
+		})
+	}
+}
+
+// countCalls returns number of calls of method with params received by s.
+func countCalls(s *getblocktest.Server, method string, params []interface{}) int {
+	want, _ := json.Marshal(params)
+	if params == nil {
+		want = []byte("[]")
+	}
+
+	n := 0
+	for _, call := range s.Calls(method) {
+		got, _ := json.Marshal(call.Params)
+		if call.Params == nil {
+			got = []byte("[]")
+		}
+
+		if string(got) == string(want) {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,95 @@
+package getblock
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type callOptionsContextKey struct{}
+
+// callOptions is per-call options attached to context.
+type callOptions struct {
+	noRetry     bool
+	timeout     time.Duration
+	endpoint    string
+	header      http.Header
+	cacheBypass bool
+}
+
+func callOptionsFromContext(ctx context.Context) callOptions {
+	opts, _ := ctx.Value(callOptionsContextKey{}).(callOptions)
+	return opts
+}
+
+func withCallOptions(ctx context.Context, set func(*callOptions)) context.Context {
+	opts := callOptionsFromContext(ctx)
+	set(&opts)
+
+	return context.WithValue(ctx, callOptionsContextKey{}, opts)
+}
+
+// WithNoRetry returns context making calls sent with it attempted once, without retries and hedging,
+// e.g. for non-idempotent calls.
+func WithNoRetry(ctx context.Context) context.Context {
+	return withCallOptions(ctx, func(o *callOptions) {
+		o.noRetry = true
+	})
+}
+
+// WithTimeout returns context limiting every call sent with it to d, retries included.
+// Unlike context.WithTimeout timeout starts with the call, so context can be reused for several calls.
+func WithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return withCallOptions(ctx, func(o *callOptions) {
+		o.timeout = d
+	})
+}
+
+// WithEndpoint returns context sending calls only to endpoint with url, bypassing endpoint selection policy
+// and health. Calls fail with ErrNoEndpoints if Client has no such endpoint.
+func WithEndpoint(ctx context.Context, url string) context.Context {
+	return withCallOptions(ctx, func(o *callOptions) {
+		o.endpoint = url
+	})
+}
+
+// WithHeader returns context adding HTTP header to calls sent with it. Headers are sent only to HTTP
+// endpoints, custom Conn of Endpoint does not receive them.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	return withCallOptions(ctx, func(o *callOptions) {
+		o.header = o.header.Clone()
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+
+		o.header.Add(key, value)
+	})
+}
+
+// WithCacheBypass returns context making calls sent with it skip cache lookup.
+// Immutable responses are still stored in cache.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return withCallOptions(ctx, func(o *callOptions) {
+		o.cacheBypass = true
+	})
+}
+
+// shareable reports whether call can share response with identical calls sent with other options.
+func (o callOptions) shareable() bool {
+	return !o.noRetry && o.endpoint == "" && len(o.header) == 0
+}
+
+// apply applies timeout of call and returns header to send with call.
+func (o callOptions) apply(ctx context.Context) (context.Context, context.CancelFunc, http.Header) {
+	header := o.header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	if o.timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, o.timeout)
+		return ctx, cancel, header
+	}
+
+	return ctx, func() {}, header
+}
@@ -0,0 +1,148 @@
+package getblock_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestWithTimeout(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_blockNumber", "0x1")
+	s.Inject(getblocktest.Fault{Times: 1, Latency: time.Second})
+
+	c := s.GetblockClient(getblock.WithRetry(1))
+	ctx := getblock.WithTimeout(context.Background(), 50*time.Millisecond)
+
+	start := time.Now()
+	if _, err := c.Call(ctx, "eth_blockNumber"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want deadline exceeded", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("call took %v", elapsed)
+	}
+
+	// timeout starts with every call, so context is reused
+	time.Sleep(60 * time.Millisecond)
+	if _, err := c.Call(ctx, "eth_blockNumber"); err != nil {
+		t.Errorf("second call: %v", err)
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_blockNumber", "0x1")
+
+	c := s.GetblockClient()
+	base := getblock.WithHeader(context.Background(), "X-Request-Id", "1")
+	ctx := getblock.WithHeader(base, "X-Request-Id", "2")
+
+	if _, err := c.Call(ctx, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Call(base, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Call(context.Background(), "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := s.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("got %d calls, want 3", len(calls))
+	}
+
+	// headers are added, context given to WithHeader is not changed
+	for i, want := range [][]string{{"1", "2"}, {"1"}, nil} {
+		if got := calls[i].Header.Values("X-Request-Id"); !reflect.DeepEqual(got, want) {
+			t.Errorf("call %d: X-Request-Id = %v, want %v", i, got, want)
+		}
+	}
+
+	if got := calls[0].Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %s, want application/json", got)
+	}
+}
+
+func TestWithEndpoint(t *testing.T) {
+	first, second := getblocktest.NewServer(), getblocktest.NewServer()
+	defer first.Close()
+	defer second.Close()
+	first.Respond("eth_blockNumber", "0x1")
+	second.Respond("eth_blockNumber", "0x2")
+
+	c := getblock.NewPool([]getblock.Endpoint{
+		{URL: first.URL, Token: getblocktest.Token},
+		{URL: second.URL, Token: getblocktest.Token},
+	})
+	defer c.Close()
+
+	ctx := getblock.WithEndpoint(context.Background(), second.URL)
+	for i := 0; i < 4; i++ {
+		r, err := c.Call(ctx, "eth_blockNumber")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(r.Result) != `"0x2"` {
+			t.Errorf("result = %s, want \"0x2\"", r.Result)
+		}
+	}
+
+	if n := len(first.Calls()); n != 0 {
+		t.Errorf("first endpoint got %d calls, want 0", n)
+	}
+
+	// endpoint is used even if it is unhealthy
+	second.Inject(getblocktest.Fault{Times: 3, Status: http.StatusBadGateway})
+	for i := 0; i < 3; i++ {
+		c.Call(ctx, "eth_blockNumber")
+	}
+	if _, err := c.Call(ctx, "eth_blockNumber"); err != nil {
+		t.Errorf("call after failures: %v", err)
+	}
+
+	unknown := getblock.WithEndpoint(context.Background(), "https://unknown.example.com/")
+	if _, err := c.Call(unknown, "eth_blockNumber"); !errors.Is(err, getblock.ErrNoEndpoints) {
+		t.Errorf("error = %v, want ErrNoEndpoints", err)
+	}
+}
+
+func TestWithNoRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "retried", ctx: context.Background(), wantCalls: 3},
+		{name: "no retry", ctx: getblock.WithNoRetry(context.Background()), wantErr: true, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			s.Respond("eth_blockNumber", "0x1")
+			s.Inject(getblocktest.Fault{Times: 2, Status: http.StatusBadGateway})
+
+			_, err := s.GetblockClient(getblock.WithRetry(3)).Call(tt.ctx, "eth_blockNumber")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if n := len(s.Calls()); n != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
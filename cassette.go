@@ -0,0 +1,355 @@
+package getblock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// CassetteMode is mode of Cassette.
+type CassetteMode int
+
+const (
+	// Replay serves recorded responses without sending requests.
+	Replay CassetteMode = iota
+	// Record sends requests and records them with responses.
+	Record
+)
+
+// Matcher reports whether recorded JSON-RPC request matches sent one.
+type Matcher func(recorded, sent *CassetteRequest) bool
+
+// MatchStrict matches requests with equal method and params. Missing params equal empty ones.
+func MatchStrict(recorded, sent *CassetteRequest) bool {
+	if recorded.Method != sent.Method {
+		return false
+	}
+
+	return len(recorded.Params) == 0 && len(sent.Params) == 0 || reflect.DeepEqual(recorded.Params, sent.Params)
+}
+
+// MatchFuzzy matches requests with equal method and params after normalization: hex strings
+// are compared case-insensitively, null fields are ignored and block parameter of methods
+// like eth_getBalance or eth_call matches any block.
+func MatchFuzzy(recorded, sent *CassetteRequest) bool {
+	if recorded.Method != sent.Method || len(recorded.Params) != len(sent.Params) {
+		return false
+	}
+
+	blockParam := -1
+	if rule, ok := cacheRules[recorded.Method]; ok && !rule.always && !rule.blockResult {
+		blockParam = rule.blockParam
+	}
+
+	for i := range recorded.Params {
+		if i == blockParam {
+			continue
+		}
+
+		a, errA := canonical(recorded.Params[i])
+		b, errB := canonical(sent.Params[i])
+		if errA != nil || errB != nil || a != b {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Cassette is http.RoundTripper recording JSON-RPC requests with their responses to file
+// and replaying them, e.g. to run tests offline. Only request bodies are recorded, never
+// URLs or headers, so cassettes do not contain tokens.
+type Cassette struct {
+	path  string
+	mode  CassetteMode
+	match Matcher
+	base  http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         map[*Interaction]bool
+}
+
+// Interaction is HTTP exchange recorded by Cassette. Batch requests have several Requests.
+type Interaction struct {
+	Requests []*CassetteRequest `json:"requests"`
+	Batch    bool               `json:"batch,omitempty"`
+	Status   int                `json:"status"`
+	Response json.RawMessage    `json:"response"`
+}
+
+// CassetteRequest is recorded JSON-RPC request.
+type CassetteRequest struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params,omitempty"`
+}
+
+// NewCassette creates Cassette stored in file at path. In Replay mode file must exist and requests
+// are matched with match, MatchStrict if nil. In Record mode requests are sent with base,
+// http.DefaultTransport if nil, and file is rewritten after every response.
+func NewCassette(path string, mode CassetteMode, match Matcher, base http.RoundTripper) (*Cassette, error) {
+	if match == nil {
+		match = MatchStrict
+	}
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	c := &Cassette{
+		path:  path,
+		mode:  mode,
+		match: match,
+		base:  base,
+		used:  make(map[*Interaction]bool),
+	}
+
+	if mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := decodeJSON(data, &c.interactions); err != nil {
+			return nil, fmt.Errorf("getblock: cassette %s: %w", path, err)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, batch, err := parseCassetteRequests(body)
+	if err != nil {
+		return nil, fmt.Errorf("getblock: cassette: %w", err)
+	}
+
+	if c.mode == Record {
+		return c.record(req, body, requests, batch)
+	}
+
+	return c.replay(req, requests, batch)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, requests []*CassetteRequest, batch bool) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	resp, err := c.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	i := &Interaction{Requests: requests, Batch: batch, Status: resp.StatusCode}
+	if json.Valid(data) {
+		i.Response = data
+	} else {
+		i.Response, _ = json.Marshal(string(data))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, i)
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0o644)
+}
+
+// replay responds with first unused matching interaction, or last matching one if all were used.
+func (c *Cassette) replay(req *http.Request, requests []*CassetteRequest, batch bool) (*http.Response, error) {
+	c.mu.Lock()
+	var found *Interaction
+	for _, i := range c.interactions {
+		if i.Batch != batch || !c.matches(i.Requests, requests) {
+			continue
+		}
+
+		found = i
+		if !c.used[i] {
+			break
+		}
+	}
+	if found != nil {
+		c.used[found] = true
+	}
+	c.mu.Unlock()
+
+	if found == nil {
+		msg := fmt.Sprintf("getblock: cassette %s: no recorded interaction for %s", c.path, describeRequests(requests))
+		data, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      requests[0].ID,
+			"error":   map[string]interface{}{"code": -32601, "message": msg},
+		})
+
+		return newResponse(req, http.StatusNotFound, data), nil
+	}
+
+	data, err := replaceIDs(found, requests)
+	if err != nil {
+		return nil, fmt.Errorf("getblock: cassette %s: %w", c.path, err)
+	}
+
+	return newResponse(req, found.Status, data), nil
+}
+
+func (c *Cassette) matches(recorded, sent []*CassetteRequest) bool {
+	if len(recorded) != len(sent) {
+		return false
+	}
+
+	for i := range recorded {
+		if !c.match(recorded[i], sent[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// replaceIDs returns recorded response with IDs of recorded requests replaced with IDs of sent ones.
+func replaceIDs(i *Interaction, sent []*CassetteRequest) ([]byte, error) {
+	ids := make(map[string]interface{}, len(sent))
+	for n, r := range i.Requests {
+		key, _ := json.Marshal(r.ID)
+		ids[string(key)] = sent[n].ID
+	}
+
+	replace := func(raw json.RawMessage) (json.RawMessage, error) {
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return raw, nil
+		}
+
+		id, ok := ids[string(bytes.TrimSpace(m["id"]))]
+		if !ok {
+			return raw, nil
+		}
+
+		data, err := json.Marshal(id)
+		if err != nil {
+			return nil, err
+		}
+		m["id"] = data
+
+		return json.Marshal(m)
+	}
+
+	if !i.Batch {
+		return replace(i.Response)
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(i.Response, &list); err != nil {
+		return i.Response, nil
+	}
+
+	for n := range list {
+		r, err := replace(list[n])
+		if err != nil {
+			return nil, err
+		}
+		list[n] = r
+	}
+
+	return json.Marshal(list)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, errors.New("getblock: cassette: request without body")
+	}
+
+	defer req.Body.Close()
+
+	return io.ReadAll(req.Body)
+}
+
+// parseCassetteRequests decodes single or batch JSON-RPC request.
+func parseCassetteRequests(body []byte) ([]*CassetteRequest, bool, error) {
+	body = bytes.TrimSpace(body)
+	batch := len(body) > 0 && body[0] == '['
+
+	var requests []*CassetteRequest
+	var err error
+	if batch {
+		err = decodeJSON(body, &requests)
+	} else {
+		var r *CassetteRequest
+		err = decodeJSON(body, &r)
+		requests = []*CassetteRequest{r}
+	}
+
+	if err == nil && (len(requests) == 0 || requests[0] == nil) {
+		err = errors.New("empty request")
+	}
+
+	return requests, batch, err
+}
+
+// decodeJSON decodes data into v keeping numbers as json.Number, as recorded params are compared with sent ones.
+func decodeJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	return d.Decode(v)
+}
+
+func describeRequests(requests []*CassetteRequest) string {
+	var buf bytes.Buffer
+	for n, r := range requests {
+		if n > 0 {
+			buf.WriteString(", ")
+		}
+
+		params, _ := json.Marshal(r.Params)
+		fmt.Fprintf(&buf, "%s%s", r.Method, params)
+	}
+
+	return buf.String()
+}
+
+func newResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
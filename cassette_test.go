@@ -0,0 +1,159 @@
+package getblock_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestCassetteReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	record(t, path)
+
+	type call struct {
+		method string
+		params []interface{}
+	}
+
+	tests := []struct {
+		name  string
+		match getblock.Matcher
+		calls []call
+		batch bool
+		// want is JSON results of calls, empty string means error.
+		want []string
+	}{
+		{
+			name:  "recorded call",
+			calls: []call{{method: "eth_getBalance", params: []interface{}{"0xABC", "0x1"}}},
+			want:  []string{`"0x64"`},
+		},
+		{
+			name:  "repeated calls in order",
+			calls: []call{{method: "eth_blockNumber"}, {method: "eth_blockNumber"}, {method: "eth_blockNumber"}},
+			want:  []string{`"0x1"`, `"0x2"`, `"0x2"`},
+		},
+		{
+			name:  "strict mismatch",
+			calls: []call{{method: "eth_getBalance", params: []interface{}{"0xabc", "0x1"}}},
+			want:  []string{""},
+		},
+		{
+			name:  "fuzzy match",
+			match: getblock.MatchFuzzy,
+			calls: []call{{method: "eth_getBalance", params: []interface{}{"0xabc", "0x2"}}},
+			want:  []string{`"0x64"`},
+		},
+		{
+			name:  "batch",
+			batch: true,
+			calls: []call{{method: "eth_chainId"}, {method: "eth_getBalance", params: []interface{}{"0xABC", "0x1"}}},
+			want:  []string{`"0x539"`, `"0x64"`},
+		},
+		{
+			name:  "not recorded",
+			calls: []call{{method: "eth_gasPrice"}},
+			want:  []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cassette, err := getblock.NewCassette(path, getblock.Replay, tt.match, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// endpoint is never contacted in replay mode
+			c := getblock.New("", "http://127.0.0.1:0/", getblock.WithRetry(1), getblock.WithHTTPClient(&http.Client{Transport: cassette}))
+
+			var got []string
+			if tt.batch {
+				batch := make([]getblock.BatchElem, len(tt.calls))
+				for i, call := range tt.calls {
+					batch[i] = getblock.BatchElem{Method: call.method, Params: call.params, Result: &json.RawMessage{}}
+				}
+
+				if err := c.CallBatch(context.Background(), batch); err != nil {
+					t.Fatal(err)
+				}
+
+				for _, elem := range batch {
+					if elem.Error != nil {
+						t.Fatal(elem.Error)
+					}
+					got = append(got, string(*elem.Result.(*json.RawMessage)))
+				}
+			} else {
+				for _, call := range tt.calls {
+					r, err := c.Call(context.Background(), call.method, call.params...)
+					if err != nil || r.Error != nil {
+						got = append(got, "")
+						continue
+					}
+					got = append(got, string(r.Result))
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d results, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("result %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// record records interactions replayed by TestCassetteReplay to cassette at path.
+func record(t *testing.T, path string) {
+	t.Helper()
+
+	s := getblocktest.NewServer()
+	defer s.Close()
+
+	var head int64
+	s.Handle("eth_blockNumber", func(*getblocktest.Call) (interface{}, error) {
+		return "0x" + strconv.FormatInt(atomic.AddInt64(&head, 1), 16), nil
+	})
+	s.Respond("eth_getBalance", "0x64")
+	s.Respond("eth_chainId", "0x539")
+
+	cassette, err := getblock.NewCassette(path, getblock.Record, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := s.GetblockClient(getblock.WithHTTPClient(&http.Client{Transport: cassette}))
+	ctx := context.Background()
+
+	for _, call := range []struct {
+		method string
+		params []interface{}
+	}{
+		{"eth_blockNumber", nil},
+		{"eth_blockNumber", nil},
+		{"eth_getBalance", []interface{}{"0xABC", "0x1"}},
+	} {
+		if _, err := c.Call(ctx, call.method, call.params...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = c.CallBatch(ctx, []getblock.BatchElem{
+		{Method: "eth_chainId"},
+		{Method: "eth_getBalance", Params: []interface{}{"0xABC", "0x1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,122 @@
+package getblock
+
+import (
+	"context"
+	"sync"
+)
+
+// nonIdempotentMethods are never deduplicated.
+var nonIdempotentMethods = []string{
+	"eth_sendRawTransaction",
+	"eth_sendTransaction",
+	"eth_newFilter",
+	"eth_newBlockFilter",
+	"eth_newPendingTransactionFilter",
+	"eth_getFilterChanges",
+	"eth_uninstallFilter",
+	"eth_submitWork",
+	"eth_submitHashrate",
+	"eth_subscribe",
+	"eth_unsubscribe",
+}
+
+// WithDeduplication makes concurrent identical calls (same method and params) share
+// single upstream request and its response. Responses are shared and must not be modified.
+// Known non-idempotent methods like eth_sendRawTransaction and methods listed in exclude
+// are never deduplicated.
+func WithDeduplication(exclude ...string) Option {
+	return func(c *Client) {
+		g := &flightGroup{
+			flights: make(map[string]*flight),
+			exclude: make(map[string]bool),
+		}
+		for _, m := range nonIdempotentMethods {
+			g.exclude[m] = true
+		}
+		for _, m := range exclude {
+			g.exclude[m] = true
+		}
+
+		c.flights = g
+	}
+}
+
+type flightGroup struct {
+	exclude map[string]bool
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	// info is outcome of attempts of shared call, copied to call info of every waiter.
+	info *callInfo
+	r    *Response
+	err  error
+}
+
+// middleware joins in-flight identical call or starts new one. Shared call is canceled
+// only when contexts of all waiting callers are done.
+func (g *flightGroup) middleware(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+		if g.exclude[req.Method] || !callOptionsFromContext(ctx).shareable() {
+			return next.Handle(ctx, req)
+		}
+
+		key, err := cacheKey(req.Method, req.Params)
+		if err != nil {
+			return next.Handle(ctx, req)
+		}
+
+		g.mu.Lock()
+		f, ok := g.flights[key]
+		if !ok {
+			callCtx, cancel := context.WithCancel(contextWithCallInfo(context.WithoutCancel(ctx)))
+			f = &flight{done: make(chan struct{}), cancel: cancel, info: callInfoFromContext(callCtx)}
+			g.flights[key] = f
+
+			go func() {
+				f.r, f.err = next.Handle(callCtx, req)
+				cancel()
+
+				g.mu.Lock()
+				g.forget(key, f)
+				g.mu.Unlock()
+
+				close(f.done)
+			}()
+		}
+		f.waiters++
+		g.mu.Unlock()
+
+		select {
+		case <-f.done:
+			if info := callInfoFromContext(ctx); info != nil {
+				info.add(f.info)
+			}
+
+			return f.r, f.err
+		case <-ctx.Done():
+			g.mu.Lock()
+			f.waiters--
+			if f.waiters == 0 {
+				// canceled flight must not be joined by later callers
+				g.forget(key, f)
+				f.cancel()
+			}
+			g.mu.Unlock()
+
+			return nil, ctx.Err()
+		}
+	})
+}
+
+// forget removes flight f of key, unless it was already replaced. g.mu must be held.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
@@ -0,0 +1,184 @@
+package getblock_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestDeduplication(t *testing.T) {
+	const callers = 5
+
+	tests := []struct {
+		name    string
+		method  string
+		exclude []string
+		// params returns params of call of i-th caller.
+		params    func(i int) []interface{}
+		ctx       func(context.Context) context.Context
+		wantCalls int
+	}{
+		{
+			name:      "identical calls",
+			method:    "eth_getBalance",
+			params:    func(int) []interface{} { return []interface{}{"0xabc", "latest"} },
+			wantCalls: 1,
+		},
+		{
+			name:      "different params",
+			method:    "eth_getBalance",
+			params:    func(i int) []interface{} { return []interface{}{"0xabc", i} },
+			wantCalls: callers,
+		},
+		{
+			name:      "non-idempotent method",
+			method:    "eth_sendRawTransaction",
+			params:    func(int) []interface{} { return []interface{}{"0x01"} },
+			wantCalls: callers,
+		},
+		{
+			name:      "excluded method",
+			method:    "eth_getBalance",
+			exclude:   []string{"eth_getBalance"},
+			params:    func(int) []interface{} { return []interface{}{"0xabc", "latest"} },
+			wantCalls: callers,
+		},
+		{
+			name:      "call with header",
+			method:    "eth_getBalance",
+			params:    func(int) []interface{} { return []interface{}{"0xabc", "latest"} },
+			ctx:       func(ctx context.Context) context.Context { return getblock.WithHeader(ctx, "X-Test", "1") },
+			wantCalls: callers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			s.Respond(tt.method, "0x1")
+			s.Inject(getblocktest.Fault{Latency: 100 * time.Millisecond})
+
+			c := s.GetblockClient(getblock.WithDeduplication(tt.exclude...))
+
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(ctx)
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, callers)
+			for i := 0; i < callers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					r, err := c.Call(ctx, tt.method, tt.params(i)...)
+					if err == nil && string(r.Result) != `"0x1"` {
+						t.Errorf("result = %s", r.Result)
+					}
+					errs <- err
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if n := len(s.Calls(tt.method)); n != tt.wantCalls {
+				t.Errorf("got %d upstream calls, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDeduplicationCanceled(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_blockNumber", "0x1")
+	s.Inject(getblocktest.Fault{Times: 1, Latency: time.Second})
+
+	c := s.GetblockClient(getblock.WithDeduplication())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Call(ctx, "eth_blockNumber"); err == nil {
+		t.Fatal("expected timeout")
+	}
+
+	// canceled flight must not be joined
+	r, err := c.Call(context.Background(), "eth_blockNumber")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(r.Result) != `"0x1"` {
+		t.Errorf("result = %s", r.Result)
+	}
+}
+
+func TestDeduplicationCallInfo(t *testing.T) {
+	const callers = 3
+
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_blockNumber", "0x1")
+	s.Inject(getblocktest.Fault{Times: 1, Latency: 100 * time.Millisecond, Status: http.StatusBadGateway})
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	c := s.GetblockClient(getblock.WithDeduplication(), getblock.WithTracerProvider(tp))
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Call(context.Background(), "eth_blockNumber"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(s.Calls("eth_blockNumber")); n != 2 {
+		t.Errorf("got %d upstream calls, want 2", n)
+	}
+
+	// every caller reports attempts of shared call
+	u, _ := url.Parse(s.URL)
+	spans := exporter.GetSpans()
+	if len(spans) != callers {
+		t.Fatalf("got %d spans, want %d", len(spans), callers)
+	}
+
+	for _, span := range spans {
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes {
+			attrs[kv.Key] = kv.Value
+		}
+
+		if v := attrs["getblock.retry_count"]; v != attribute.IntValue(1) {
+			t.Errorf("retry count = %s, want 1", v.Emit())
+		}
+
+		if v := attrs["server.address"]; v != attribute.StringValue(u.Host) {
+			t.Errorf("server address = %s, want %s", v.Emit(), u.Host)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ofen/getblock-go"
+)
+
+// Node client names reported in Capabilities.
+const (
+	ClientGeth       = "Geth"
+	ClientErigon     = "Erigon"
+	ClientBesu       = "Besu"
+	ClientNethermind = "Nethermind"
+	ClientReth       = "Reth"
+)
+
+// ErrUnsupported is returned when method is not supported by the node.
+var ErrUnsupported = errors.New("unsupported by this node")
+
+// UnsupportedError is returned when method or its namespace is not supported by the node.
+// It matches ErrUnsupported with errors.Is.
+type UnsupportedError struct {
+	// Method is method or namespace not supported by the node.
+	Method string
+	// Client is node client name, empty if capabilities were not probed.
+	Client string
+	// Err is JSON-RPC error returned by the node, nil if request was not sent.
+	Err error
+}
+
+func (e *UnsupportedError) Error() string {
+	if e.Client != "" {
+		return fmt.Sprintf("%s: %v (%s)", e.Method, ErrUnsupported, e.Client)
+	}
+
+	return fmt.Sprintf("%s: %v", e.Method, ErrUnsupported)
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+func (e *UnsupportedError) Unwrap() error {
+	return e.Err
+}
+
+// ClientVersion returns the current client version.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/web3_clientVersion
+func (c *Client) ClientVersion(ctx context.Context) (string, error) {
+	return call[string](ctx, c, "web3_clientVersion")
+}
+
+// RPCModules lists enabled APIs and the version of each.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/rpc_modules
+func (c *Client) RPCModules(ctx context.Context) (map[string]string, error) {
+	return call[map[string]string](ctx, c, "rpc_modules")
+}
+
+// Capabilities probes node client and enabled namespaces. Result is remembered by the client,
+// after that requests to namespaces not enabled on the node fail with UnsupportedError without being sent.
+// If client has several endpoints (see getblock.NewPool), each of them is probed and only namespaces
+// enabled on all endpoints are reported, node client and version are those of the first endpoint.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	var endpoints []string
+	if l, ok := c.Client.(endpointLister); ok {
+		endpoints = l.Endpoints()
+	}
+
+	if len(endpoints) < 2 {
+		endpoints = []string{""}
+	}
+
+	var caps *Capabilities
+	for _, endpoint := range endpoints {
+		ctx := ctx
+		if endpoint != "" {
+			ctx = getblock.WithCacheBypass(getblock.WithEndpoint(ctx, endpoint))
+		}
+
+		probed, err := c.probe(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if caps == nil {
+			caps = probed
+			continue
+		}
+
+		caps.Modules = intersectModules(caps.Modules, probed.Modules)
+	}
+
+	c.mu.Lock()
+	c.capabilities = caps
+	c.mu.Unlock()
+
+	return caps, nil
+}
+
+// probe returns capabilities of endpoint serving ctx.
+func (c *Client) probe(ctx context.Context) (*Capabilities, error) {
+	version, err := c.ClientVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := c.RPCModules(ctx)
+	if err != nil && !errors.Is(err, ErrUnsupported) {
+		return nil, err
+	}
+
+	caps := parseClientVersion(version)
+	caps.Modules = modules
+
+	return caps, nil
+}
+
+// intersectModules returns namespaces enabled in both a and b. Nil means unknown namespaces.
+func intersectModules(a, b map[string]string) map[string]string {
+	if a == nil {
+		return b
+	}
+
+	if b == nil {
+		return a
+	}
+
+	modules := make(map[string]string)
+	for ns, v := range a {
+		if _, ok := b[ns]; ok {
+			modules[ns] = v
+		}
+	}
+
+	return modules
+}
+
+// Capabilities is node client and enabled namespaces.
+type Capabilities struct {
+	// Client is one of ClientGeth, ClientErigon, ClientBesu, ClientNethermind, ClientReth
+	// or client name as reported by the node.
+	Client string
+	// Version is client version without "v" prefix and build metadata, e.g. "1.13.5".
+	Version string
+	// ClientVersion is raw result of web3_clientVersion.
+	ClientVersion string
+	// Modules is enabled namespaces and their versions, nil if node does not expose rpc_modules.
+	Modules map[string]string
+}
+
+// Supports reports whether namespace (e.g. "trace", "debug", "txpool") is enabled on the node.
+// All namespaces are reported as supported if enabled namespaces are unknown.
+func (c *Capabilities) Supports(namespace string) bool {
+	if c.Modules == nil {
+		return true
+	}
+
+	_, ok := c.Modules[namespace]
+
+	return ok
+}
+
+// Require returns UnsupportedError if any of namespaces is not enabled on the node.
+func (c *Capabilities) Require(namespaces ...string) error {
+	for _, ns := range namespaces {
+		if !c.Supports(ns) {
+			return &UnsupportedError{Method: ns, Client: c.Client}
+		}
+	}
+
+	return nil
+}
+
+// checkSupported fails fast if namespace of method is known to be not enabled on the node.
+func (c *Client) checkSupported(method string) error {
+	c.mu.Lock()
+	caps := c.capabilities
+	c.mu.Unlock()
+
+	if caps == nil || method == "rpc_modules" {
+		return nil
+	}
+
+	ns := method
+	if i := strings.IndexByte(method, '_'); i != -1 {
+		ns = method[:i]
+	}
+
+	if !caps.Supports(ns) {
+		return &UnsupportedError{Method: method, Client: caps.Client}
+	}
+
+	return nil
+}
+
+func (c *Client) unsupported(method string, err error) error {
+	c.mu.Lock()
+	caps := c.capabilities
+	c.mu.Unlock()
+
+	e := &UnsupportedError{Method: method, Err: err}
+	if caps != nil {
+		e.Client = caps.Client
+	}
+
+	return e
+}
+
+func parseClientVersion(s string) *Capabilities {
+	caps := &Capabilities{ClientVersion: s}
+
+	parts := strings.Split(s, "/")
+	caps.Client = parts[0]
+	switch strings.ToLower(parts[0]) {
+	case "geth":
+		caps.Client = ClientGeth
+	case "erigon":
+		caps.Client = ClientErigon
+	case "besu":
+		caps.Client = ClientBesu
+	case "nethermind":
+		caps.Client = ClientNethermind
+	case "reth":
+		caps.Client = ClientReth
+	}
+
+	if len(parts) > 1 {
+		v := strings.TrimPrefix(parts[1], "v")
+		if i := strings.IndexAny(v, "-+"); i != -1 {
+			v = v[:i]
+		}
+		caps.Version = v
+	}
+
+	return caps
+}
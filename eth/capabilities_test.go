@@ -0,0 +1,151 @@
+package eth_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/eth"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		clientVersion string
+		wantClient    string
+		wantVersion   string
+	}{
+		{clientVersion: "Geth/v1.13.5-stable-916d6a44/linux-amd64/go1.21.4", wantClient: eth.ClientGeth, wantVersion: "1.13.5"},
+		{clientVersion: "erigon/2.55.1/linux-amd64/go1.21.5", wantClient: eth.ClientErigon, wantVersion: "2.55.1"},
+		{clientVersion: "besu/v23.10.2/linux-x86_64/openjdk-java-17", wantClient: eth.ClientBesu, wantVersion: "23.10.2"},
+		{clientVersion: "Nethermind/v1.25.4+20b10b35/linux-x64/dotnet8.0.2", wantClient: eth.ClientNethermind, wantVersion: "1.25.4"},
+		{clientVersion: "reth/v0.1.0-alpha.13-1a0ba2b/x86_64-unknown-linux-gnu", wantClient: eth.ClientReth, wantVersion: "0.1.0"},
+		{clientVersion: "CustomNode/v2.0.0", wantClient: "CustomNode", wantVersion: "2.0.0"},
+		{clientVersion: "mystery", wantClient: "mystery"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.clientVersion, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			s.Respond("web3_clientVersion", tt.clientVersion)
+			s.Respond("rpc_modules", map[string]string{"eth": "1.0"})
+
+			caps, err := eth.NewClient(s.GetblockClient()).Capabilities(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if caps.Client != tt.wantClient || caps.Version != tt.wantVersion || caps.ClientVersion != tt.clientVersion {
+				t.Errorf("capabilities = %+v, want client %q version %q", caps, tt.wantClient, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestCapabilitiesFailFast(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("web3_clientVersion", "Geth/v1.13.5")
+	s.Respond("rpc_modules", map[string]string{"eth": "1.0", "web3": "1.0"})
+
+	c := eth.NewClient(s.GetblockClient())
+	if _, err := c.Capabilities(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.Transaction(context.Background(), "0xa")
+
+	var unsupported *eth.UnsupportedError
+	if !errors.As(err, &unsupported) || !errors.Is(err, eth.ErrUnsupported) {
+		t.Fatalf("error = %v, want UnsupportedError", err)
+	}
+
+	if unsupported.Method != "trace_transaction" || unsupported.Client != eth.ClientGeth || unsupported.Err != nil {
+		t.Errorf("error = %+v", unsupported)
+	}
+
+	if calls := s.Calls("trace_transaction"); len(calls) != 0 {
+		t.Errorf("got %d trace_transaction calls, want 0", len(calls))
+	}
+
+	if err := (&eth.Capabilities{Modules: map[string]string{"eth": "1.0"}}).Require("eth", "debug"); !errors.Is(err, eth.ErrUnsupported) {
+		t.Errorf("Require error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestCapabilitiesMethodNotFound(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("web3_clientVersion", "besu/v23.10.2")
+
+	c := eth.NewClient(s.GetblockClient())
+
+	// node without rpc_modules reports all namespaces as supported
+	caps, err := c.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if caps.Modules != nil || !caps.Supports("trace") {
+		t.Errorf("capabilities = %+v, want unknown modules", caps)
+	}
+
+	_, err = c.Transaction(context.Background(), "0xa")
+
+	var unsupported *eth.UnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Client != eth.ClientBesu {
+		t.Fatalf("error = %v, want UnsupportedError of Besu", err)
+	}
+
+	var rpcErr *getblock.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != getblocktest.CodeMethodNotFound {
+		t.Errorf("error = %v, want wrapped method not found", err)
+	}
+}
+
+func TestCapabilitiesPool(t *testing.T) {
+	modules := []map[string]string{
+		{"eth": "1.0", "trace": "1.0", "debug": "1.0"},
+		{"eth": "1.0", "trace": "1.0"},
+		nil,
+	}
+
+	var servers []*getblocktest.Server
+	var endpoints []getblock.Endpoint
+	for i, m := range modules {
+		s := getblocktest.NewServer()
+		defer s.Close()
+		s.Respond("web3_clientVersion", []string{"Geth/v1.13.5", "erigon/2.55.1", "reth/v0.1.0"}[i])
+		if m != nil {
+			s.Respond("rpc_modules", m)
+		}
+
+		servers = append(servers, s)
+		endpoints = append(endpoints, getblock.Endpoint{URL: s.URL, Token: getblocktest.Token})
+	}
+
+	pool := getblock.NewPool(endpoints)
+	defer pool.Close()
+
+	caps, err := eth.NewClient(pool).Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]string{"eth": "1.0", "trace": "1.0"}; !reflect.DeepEqual(caps.Modules, want) {
+		t.Errorf("modules = %v, want %v", caps.Modules, want)
+	}
+
+	if caps.Client != eth.ClientGeth {
+		t.Errorf("client = %s, want %s", caps.Client, eth.ClientGeth)
+	}
+
+	for i, s := range servers {
+		if calls := s.Calls("web3_clientVersion"); len(calls) != 1 {
+			t.Errorf("endpoint %d: got %d web3_clientVersion calls, want 1", i, len(calls))
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+
+	"github.com/ofen/getblock-go"
+)
+
+// Option configures Client.
+type Option func(*Client)
+
+// WithChainID makes Client verify on first call that endpoint serves chain with chainID.
+// Calls fail with ChainIDMismatchError without being sent if it does not. Every endpoint of
+// client balancing several endpoints (see getblock.NewPool) is verified. Nil chainID disables verification.
+func WithChainID(chainID *big.Int) Option {
+	return func(c *Client) {
+		c.expectedChainID = nil
+		if chainID != nil {
+			c.expectedChainID = new(big.Int).Set(chainID)
+		}
+	}
+}
+
+// ChainIDMismatchError is returned when endpoint serves chain other than expected.
+type ChainIDMismatchError struct {
+	Expected *big.Int
+	Actual   *big.Int
+	// Host is host of endpoint serving other chain if client has several endpoints.
+	Host string
+}
+
+func (e *ChainIDMismatchError) Error() string {
+	if e.Host != "" {
+		return fmt.Sprintf("chain ID mismatch: expected %s, endpoint %s serves %s", e.Expected, e.Host, e.Actual)
+	}
+
+	return fmt.Sprintf("chain ID mismatch: expected %s, endpoint serves %s", e.Expected, e.Actual)
+}
+
+// endpointLister is client of several endpoints, e.g. getblock.Client.
+type endpointLister interface {
+	Endpoints() []string
+}
+
+// ChainID returns the chain ID used for signing replay-protected transactions. Result is remembered
+// by the client, so it can be requested before signing every transaction.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_chainId
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	id := c.chainID
+	c.mu.Unlock()
+
+	if id != nil {
+		return new(big.Int).Set(id), nil
+	}
+
+	v, err := send[string](ctx, c, "eth_chainId")
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := new(big.Int).SetString(v, 0)
+	if !ok {
+		return nil, fmt.Errorf("eth_chainId: invalid quantity %q", v)
+	}
+
+	c.mu.Lock()
+	c.chainID = id
+	c.mu.Unlock()
+
+	return new(big.Int).Set(id), nil
+}
+
+// Version returns the network ID.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/net_version
+func (c *Client) Version(ctx context.Context) (string, error) {
+	return call[string](ctx, c, "net_version")
+}
+
+// Verify returns ChainIDMismatchError if endpoint does not serve chain with expectedChainID.
+// If client has several endpoints (see getblock.NewPool), each of them is asked for chain ID
+// and all must serve expected chain.
+func (c *Client) Verify(ctx context.Context, expectedChainID *big.Int) error {
+	if expectedChainID == nil {
+		return errors.New("verify chain ID: expected chain ID is nil")
+	}
+
+	var endpoints []string
+	if l, ok := c.Client.(endpointLister); ok {
+		endpoints = l.Endpoints()
+	}
+
+	if len(endpoints) < 2 {
+		id, err := c.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+
+		if id.Cmp(expectedChainID) != 0 {
+			return &ChainIDMismatchError{Expected: new(big.Int).Set(expectedChainID), Actual: id}
+		}
+
+		return nil
+	}
+
+	for _, endpoint := range endpoints {
+		// cached chain ID may come from other endpoint
+		ctx := getblock.WithCacheBypass(getblock.WithEndpoint(ctx, endpoint))
+		v, err := send[string](ctx, c, "eth_chainId")
+		if err != nil {
+			return err
+		}
+
+		id, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return fmt.Errorf("eth_chainId: invalid quantity %q", v)
+		}
+
+		if id.Cmp(expectedChainID) != 0 {
+			var host string
+			if u, err := url.Parse(endpoint); err == nil {
+				host = u.Host
+			}
+
+			return &ChainIDMismatchError{Expected: new(big.Int).Set(expectedChainID), Actual: id, Host: host}
+		}
+	}
+
+	return nil
+}
+
+// verifyChainID verifies chain ID set with WithChainID until verification succeeds.
+func (c *Client) verifyChainID(ctx context.Context) error {
+	if c.expectedChainID == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	verified := c.chainVerified
+	c.mu.Unlock()
+
+	if verified {
+		return nil
+	}
+
+	if err := c.Verify(ctx, c.expectedChainID); err != nil {
+		return fmt.Errorf("verify chain ID: %w", err)
+	}
+
+	c.mu.Lock()
+	c.chainVerified = true
+	c.mu.Unlock()
+
+	return nil
+}
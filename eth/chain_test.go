@@ -0,0 +1,92 @@
+package eth_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/url"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/eth"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestWithChainID(t *testing.T) {
+	tests := []struct {
+		name     string
+		chainIDs []string
+		expected *big.Int
+		opts     []getblock.Option
+		// mismatch is index of endpoint serving other chain, -1 if none.
+		mismatch int
+	}{
+		{name: "single endpoint", chainIDs: []string{"0x1"}, expected: big.NewInt(1), mismatch: -1},
+		{name: "single endpoint mismatch", chainIDs: []string{"0x5"}, expected: big.NewInt(1), mismatch: 0},
+		{name: "pool", chainIDs: []string{"0x1", "0x1", "0x1"}, expected: big.NewInt(1), mismatch: -1},
+		{name: "pool mismatch", chainIDs: []string{"0x1", "0x5", "0x1"}, expected: big.NewInt(1), mismatch: 1},
+		{
+			name:     "pool mismatch with cache",
+			chainIDs: []string{"0x1", "0x5"},
+			expected: big.NewInt(1),
+			opts:     []getblock.Option{getblock.WithCache(getblock.NewLRUCache(0, 0))},
+			mismatch: 1,
+		},
+		{name: "nil disables verification", chainIDs: []string{"0x5", "0x1"}, mismatch: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var servers []*getblocktest.Server
+			var endpoints []getblock.Endpoint
+			for _, id := range tt.chainIDs {
+				s := getblocktest.NewServer()
+				defer s.Close()
+				s.Respond("eth_chainId", id)
+				s.Respond("eth_blockNumber", "0x10")
+
+				servers = append(servers, s)
+				endpoints = append(endpoints, getblock.Endpoint{URL: s.URL, Token: getblocktest.Token})
+			}
+
+			pool := getblock.NewPool(endpoints, tt.opts...)
+			defer pool.Close()
+			c := eth.NewClient(pool, eth.WithChainID(tt.expected))
+
+			for i := 0; i < 2; i++ {
+				_, err := c.BlockNumber(context.Background())
+
+				if tt.mismatch < 0 {
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					continue
+				}
+
+				var mismatchErr *eth.ChainIDMismatchError
+				if !errors.As(err, &mismatchErr) {
+					t.Fatalf("error = %v, want ChainIDMismatchError", err)
+				}
+
+				if len(servers) > 1 {
+					u, _ := url.Parse(servers[tt.mismatch].URL)
+					if mismatchErr.Host != u.Host {
+						t.Errorf("host = %s, want %s", mismatchErr.Host, u.Host)
+					}
+				}
+			}
+
+			for i, s := range servers {
+				if tt.mismatch >= 0 && len(s.Calls("eth_blockNumber")) != 0 {
+					t.Errorf("endpoint %d: call sent despite mismatch", i)
+				}
+
+				// verification succeeds once
+				if n := len(s.Calls("eth_chainId")); tt.expected != nil && tt.mismatch < 0 && len(servers) > 1 && n != 1 {
+					t.Errorf("endpoint %d: got %d eth_chainId calls, want 1", i, n)
+				}
+			}
+		})
+	}
+}
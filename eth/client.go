@@ -2,8 +2,11 @@ package eth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/ofen/getblock-go"
 )
@@ -29,45 +32,46 @@ const (
 const Endpoint = "https://eth.getblock.io/mainnet/"
 
 // New creates JSON-RPC client for https://eth.getblock.io/mainnet/.
-func New(token string) *Client {
-	return &Client{getblock.New(token, Endpoint)}
+func New(token string, opts ...Option) *Client {
+	return NewClient(getblock.New(token, Endpoint), opts...)
+}
+
+// NewClient creates JSON-RPC client sending calls with caller, e.g. getblock.Client configured
+// with options, getblock.Quorum or fake.
+func NewClient(caller getblock.Caller, opts ...Option) *Client {
+	c := &Client{Client: caller}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // Client is JSON-RPC client
 type Client struct {
-	Client *getblock.Client
+	Client getblock.Caller
+	// PollInterval is interval of filter polling of subscriptions, default is 4 seconds.
+	PollInterval time.Duration
+
+	expectedChainID *big.Int
+
+	mu           sync.Mutex
+	capabilities *Capabilities
+	chainID      *big.Int
+	// chainVerified is true once chain ID set with WithChainID was verified.
+	chainVerified bool
 }
 
 // BlockNumber returns the index corresponding to the block number of the current chain head
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_blockNumber/.
 func (c *Client) BlockNumber(ctx context.Context) (*big.Int, error) {
-	r, err := c.Client.Call(ctx, "eth_blockNumber")
-	if err != nil {
-		return nil, err
-	}
-
-	v, ok := r.Result.(string)
-	if !ok {
-		return nil, fmt.Errorf("value is not string: %v", r.Result)
-	}
-
-	return hex2int(v), nil
+	return callInt(ctx, c, "eth_blockNumber")
 }
 
-// GetBlockByNumber returns information about a block by block number
+// GetBlockByNumber returns information about a block by block number, nil blockNumber means latest block
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getBlockByNumber/.
 func (c *Client) GetBlockByNumber(ctx context.Context, blockNumber *big.Int, detailedTransactions bool) (*Block, error) {
-	bn := int2hex(blockNumber)
-	r, err := c.Client.Call(ctx, "eth_getBlockByNumber", bn, detailedTransactions)
-	if err != nil {
-		return nil, err
-	}
-
-	v := &Block{}
-	err = r.GetObject(&v)
-
-	return v, err
-
+	return call[*Block](ctx, c, "eth_getBlockByNumber", blockNumberArg(blockNumber), detailedTransactions)
 }
 
 // Accounts returns a list of account addresses a client owns.
@@ -82,12 +86,9 @@ func (c *Client) Accounts() {}
 // If revert reason is enabled with --revert-reason-enabled, the eth_call error response will include the revert reason.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_call
-func (c *Client) Call() {}
-
-// ChainID Returns the chain ID.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_chainId
-func (c *Client) ChainID() {}
+func (c *Client) Call(ctx context.Context, msg CallMsg, blockNumber *big.Int) (string, error) {
+	return call[string](ctx, c, "eth_call", msg, blockNumberArg(blockNumber))
+}
 
 // Coinbase returns the client coinbase address. The coinbase address is the account to pay mining rewards to.
 //
@@ -103,24 +104,32 @@ func (c *Client) Coinbase() {}
 // If revert reason is enabled with --revert-reason-enabled, the eth_estimateGas error response will include the revert reason.
 //
 //https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_estimateGas
-func (c *Client) EstimateGas() {}
+func (c *Client) EstimateGas(ctx context.Context, msg CallMsg) (*big.Int, error) {
+	return callInt(ctx, c, "eth_estimateGas", msg)
+}
 
 // GasPrice returns a percentile gas unit price for the most recent blocks, in Wei. By default, the last 100 blocks are examined and the 50th percentile gas unit price (that is, the median value) is returned.
 //
 // If there are no blocks, the value for --min-gas-price is returned. The value returned is restricted to values between --min-gas-price and --api-gas-price-max. By default, 1000 Wei and 500GWei.
 // Use the --api-gas-price-blocks, --api-gas-price-percentile , and --api-gas-price-max command line options to configure the eth_gasPrice default values.
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_gasPrice
-func (c *Client) GasPrice() {}
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	return callInt(ctx, c, "eth_gasPrice")
+}
 
 // GetBalance returns the account balance of the specified address.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getBalance
-func (c *Client) GetBalance() {}
+func (c *Client) GetBalance(ctx context.Context, address string, blockNumber *big.Int) (*big.Int, error) {
+	return callInt(ctx, c, "eth_getBalance", address, blockNumberArg(blockNumber))
+}
 
 // GetBlockByHash returns information about the block by hash.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getBlockByHash
-func (c *Client) GetBlockByHash() {}
+func (c *Client) GetBlockByHash(ctx context.Context, hash string, detailedTransactions bool) (*Block, error) {
+	return call[*Block](ctx, c, "eth_getBlockByHash", hash, detailedTransactions)
+}
 
 // GetBlockTransactionCountByHash returns the number of transactions in the block matching the given block hash.
 //
@@ -135,25 +144,33 @@ func (c *Client) GetBlockTransactionCountByNumber() {}
 // GetCode returns the code of the smart contract at the specified address. Besu stores compiled smart contract code as a hexadecimal value.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getCode
-func (c *Client) GetCode() {}
+func (c *Client) GetCode(ctx context.Context, address string, blockNumber *big.Int) (string, error) {
+	return call[string](ctx, c, "eth_getCode", address, blockNumberArg(blockNumber))
+}
 
 // GetFilterChanges polls the specified filter and returns an array of changes that have occurred since the last poll.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getFilterChanges
-func (c *Client) GetFilterChanges() {}
+func (c *Client) GetFilterChanges(ctx context.Context, id string) (FilterChanges, error) {
+	return call[FilterChanges](ctx, c, "eth_getFilterChanges", id)
+}
 
 // GetFilterLogs returns an array of logs for the specified filter.
 // Leave the --auto-log-bloom-caching-enabled command line option at the default value of true to improve log retrieval performance.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getFilterLogs
-func (c *Client) GetFilterLogs() {}
+func (c *Client) GetFilterLogs(ctx context.Context, id string) ([]Log, error) {
+	return call[[]Log](ctx, c, "eth_getFilterLogs", id)
+}
 
 // GetLogs returns an array of logs matching a specified filter object.
 //
 // Leave the --auto-log-bloom-caching-enabled command line option at the default value of true to improve log retrieval performance.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getLogs
-func (c *Client) GetLogs() {}
+func (c *Client) GetLogs(ctx context.Context, q FilterQuery) ([]Log, error) {
+	return call[[]Log](ctx, c, "eth_getLogs", q)
+}
 
 // GetMinerDataByBlockHash returns miner data for the specified block.
 //
@@ -165,17 +182,12 @@ func (c *Client) GetMinerDataByBlockHash() {}
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getMinerDataByBlockNumber
 func (c *Client) GetMinerDataByBlockNumber() {}
 
-// GetProof returns the account and storage values of the specified account, including the Merkle proof.
-//
-// The API allows IoT devices or mobile apps which are unable to run light clients to verify responses from untrusted sources, by using a trusted block hash.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getProof
-func (c *Client) GetProof() {}
-
 // GetStorageAt returns the value of a storage position at a specified address.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getStorageAt
-func (c *Client) GetStorageAt() {}
+func (c *Client) GetStorageAt(ctx context.Context, address string, position string, blockNumber *big.Int) (string, error) {
+	return call[string](ctx, c, "eth_getStorageAt", address, position, blockNumberArg(blockNumber))
+}
 
 // GetTransactionByBlockHashAndIndex returns transaction information for the specified block hash and transaction index position.
 //
@@ -190,19 +202,25 @@ func (c *Client) GetTransactionByBlockNumberAndIndex() {}
 // GetTransactionByHash returns transaction information for the specified transaction hash.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getTransactionByHash
-func (c *Client) GetTransactionByHash() {}
+func (c *Client) GetTransactionByHash(ctx context.Context, hash string) (*Transaction, error) {
+	return call[*Transaction](ctx, c, "eth_getTransactionByHash", hash)
+}
 
 // GetTransactionCount returns the number of transactions sent from a specified address. Use the pending tag to get the next account nonce not used by any pending transactions.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getTransactionCount
-func (c *Client) GetTransactionCount() {}
+func (c *Client) GetTransactionCount(ctx context.Context, address string, blockNumber *big.Int) (*big.Int, error) {
+	return callInt(ctx, c, "eth_getTransactionCount", address, blockNumberArg(blockNumber))
+}
 
 // GetTransactionReceipt returns the receipt of a transaction by transaction hash. Receipts for pending transactions are not available.
 //
 // If you enabled revert reason, the receipt includes available revert reasons in the response.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getTransactionReceipt
-func (c *Client) GetTransactionReceipt() {}
+func (c *Client) GetTransactionReceipt(ctx context.Context, hash string) (*Receipt, error) {
+	return call[*Receipt](ctx, c, "eth_getTransactionReceipt", hash)
+}
 
 // GetUncleByBlockHashAndIndex returns uncle specified by block hash and index.
 //
@@ -244,17 +262,23 @@ func (c *Client) Mining() {}
 // NewBlockFilter creates a filter to retrieve new block hashes. To poll for new blocks, use eth_getFilterChanges.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_newBlockFilter
-func (c *Client) NewBlockFilter() {}
+func (c *Client) NewBlockFilter(ctx context.Context) (string, error) {
+	return call[string](ctx, c, "eth_newBlockFilter")
+}
 
 // NewFilter creates a log filter. To poll for logs associated with the created filter, use eth_getFilterChanges. To get all logs associated with the filter, use eth_getFilterLogs.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_newFilter
-func (c *Client) NewFilter() {}
+func (c *Client) NewFilter(ctx context.Context, q FilterQuery) (string, error) {
+	return call[string](ctx, c, "eth_newFilter", q)
+}
 
 // NewPendingTransactionFilter creates a filter to retrieve new pending transactions hashes. To poll for new pending transactions, use eth_getFilterChanges.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_newPendingTransactionFilter
-func (c *Client) NewPendingTransactionFilter() {}
+func (c *Client) NewPendingTransactionFilter(ctx context.Context) (string, error) {
+	return call[string](ctx, c, "eth_newPendingTransactionFilter")
+}
 
 // ProtocolVersion returns current Ethereum protocol version.
 //
@@ -268,7 +292,9 @@ func (c *Client) ProtocolVersion() {}
 // To avoid exposing your private key, create signed transactions offline and send the signed transaction data using eth_sendRawTransaction.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_sendRawTransaction
-func (c *Client) SendRawTransaction() {}
+func (c *Client) SendRawTransaction(ctx context.Context, data string) (string, error) {
+	return call[string](ctx, c, "eth_sendRawTransaction", data)
+}
 
 // SubmitHashrate submits the mining hashrate.
 //
@@ -294,7 +320,9 @@ func (c *Client) Syncing() {}
 // Filters time out when not requested by eth_getFilterChanges or eth_getFilterLogs for 10 minutes.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_uninstallFilter
-func (c *Client) UninstallFilter() {}
+func (c *Client) UninstallFilter(ctx context.Context, id string) (bool, error) {
+	return call[bool](ctx, c, "eth_uninstallFilter", id)
+}
 
 // Enode returns the enode URL.
 //
@@ -316,56 +344,11 @@ func (c *Client) PeerCount() {}
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/net_services
 func (c *Client) Services() {}
 
-// Version returns the network ID.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/net_version
-func (c *Client) Version() {}
-
-// Block provides transaction processing of type trace for the specified block.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_block
-func (c *Client) Block() {}
-
-// ReplayBlockTransactions provides transaction processing tracing per block.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_replayBlockTransactions
-func (c *Client) ReplayBlockTransactions() {}
-
-// Transaction provides transaction processing of type trace for the specified transaction.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_transaction
-func (c *Client) Transaction() {}
-
-// BesuPendingTransactions lists pending transactions that match the supplied filter conditions.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/txpool_besuPendingTransactions
-func (c *Client) BesuPendingTransactions() {}
-
-// BesuStatistics lists statistics about the node transaction pool.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/txpool_besuStatistics
-func (c *Client) BesuStatistics() {}
-
-// BesuTransactions lists transactions in the node transaction pool.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/txpool_besuTransactions
-func (c *Client) BesuTransactions() {}
-
-// ClientVersion returns the current client version.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/web3_clientVersion
-func (c *Client) ClientVersion() {}
-
 // SHA3 returns a SHA3 hash of the specified data. The result value is a Keccak-256 hash, not the standardized SHA3-256.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/web3_sha3
 func (c *Client) SHA3() {}
 
-// RPCModules lists enabled APIs and the version of each.
-//
-// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/rpc_modules
-func (c *Client) RPCModules() {}
-
 // ReloadPluginConfig reloads specified plugin configuration.
 //
 // https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/plugins_reloadPluginConfig
@@ -394,3 +377,61 @@ func hex2int(s string) *big.Int {
 func int2hex(i *big.Int) string {
 	return fmt.Sprintf("%#x", i)
 }
+
+// blockNumberArg returns block parameter for given block number, nil means latest block.
+func blockNumberArg(i *big.Int) string {
+	if i == nil {
+		return "latest"
+	}
+
+	return int2hex(i)
+}
+
+// callInt sends request and decodes hex quantity result.
+func callInt(ctx context.Context, c *Client, method string, params ...interface{}) (*big.Int, error) {
+	v, err := call[string](ctx, c, method, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	i := new(big.Int)
+	if v != "" {
+		if _, ok := i.SetString(v, 0); !ok {
+			return nil, fmt.Errorf("%s: invalid quantity %q", method, v)
+		}
+	}
+
+	return i, nil
+}
+
+// call sends request with getblock.CallFor after verifying chain ID set with WithChainID.
+func call[T any](ctx context.Context, c *Client, method string, params ...interface{}) (T, error) {
+	if err := c.verifyChainID(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return send[T](ctx, c, method, params...)
+}
+
+// send sends request with getblock.CallFor.
+// Requests to namespaces known to be unsupported by the node fail without sending.
+func send[T any](ctx context.Context, c *Client, method string, params ...interface{}) (T, error) {
+	if err := c.checkSupported(method); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	v, err := getblock.CallFor[T](ctx, c.Client, method, params...)
+	if isMethodNotFound(err) {
+		return v, c.unsupported(method, err)
+	}
+
+	return v, err
+}
+
+// isMethodNotFound reports whether err is JSON-RPC "method not found" error.
+func isMethodNotFound(err error) bool {
+	var e *getblock.RPCError
+	return errors.As(err, &e) && e.Code == -32601
+}
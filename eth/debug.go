@@ -0,0 +1,253 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+const (
+	// CallTracer is built-in tracer name which returns tree of call frames.
+	CallTracer = "callTracer"
+	// PrestateTracer is built-in tracer name which returns accounts state touched by transaction.
+	PrestateTracer = "prestateTracer"
+)
+
+// TraceConfig is options of debug_trace* methods. Struct logger is used if Tracer is empty.
+// Tracer can be built-in tracer name or JavaScript tracer code.
+type TraceConfig struct {
+	Tracer           string      `json:"tracer,omitempty"`
+	TracerConfig     interface{} `json:"tracerConfig,omitempty"`
+	Timeout          string      `json:"timeout,omitempty"`
+	Reexec           uint64      `json:"reexec,omitempty"`
+	DisableStorage   bool        `json:"disableStorage,omitempty"`
+	DisableStack     bool        `json:"disableStack,omitempty"`
+	EnableMemory     bool        `json:"enableMemory,omitempty"`
+	EnableReturnData bool        `json:"enableReturnData,omitempty"`
+}
+
+// CallTracerConfig is TracerConfig of CallTracer.
+type CallTracerConfig struct {
+	OnlyTopCall bool `json:"onlyTopCall,omitempty"`
+	WithLog     bool `json:"withLog,omitempty"`
+}
+
+// PrestateTracerConfig is TracerConfig of PrestateTracer.
+type PrestateTracerConfig struct {
+	DiffMode bool `json:"diffMode,omitempty"`
+}
+
+// DebugTraceTransaction returns trace of the specified transaction produced by tracer given in cfg.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/debug_traceTransaction
+func (c *Client) DebugTraceTransaction(ctx context.Context, hash string, cfg *TraceConfig) (DebugTrace, error) {
+	return call[DebugTrace](ctx, c, "debug_traceTransaction", hash, cfg)
+}
+
+// DebugTraceCall executes message call on top of the specified block and returns trace produced by tracer given in cfg.
+// Nil blockNumber means latest block.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/debug_traceCall
+func (c *Client) DebugTraceCall(ctx context.Context, msg CallMsg, blockNumber *big.Int, cfg *TraceConfig) (DebugTrace, error) {
+	return call[DebugTrace](ctx, c, "debug_traceCall", msg, blockNumberArg(blockNumber), cfg)
+}
+
+// DebugTraceBlockByNumber returns traces of all transactions of the specified block produced by tracer given in cfg.
+// Nil blockNumber means latest block.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/debug_traceBlockByNumber
+func (c *Client) DebugTraceBlockByNumber(ctx context.Context, blockNumber *big.Int, cfg *TraceConfig) ([]BlockTrace, error) {
+	return call[[]BlockTrace](ctx, c, "debug_traceBlockByNumber", blockNumberArg(blockNumber), cfg)
+}
+
+// BlockTrace is trace of single transaction in block.
+type BlockTrace struct {
+	TxHash string     `json:"txHash"`
+	Result DebugTrace `json:"result"`
+	Error  string     `json:"error"`
+}
+
+// DebugTrace is raw result of debug_trace* methods. Its format depends on tracer used,
+// results of custom JavaScript tracers can be decoded with json.Unmarshal.
+type DebugTrace []byte
+
+func (t DebugTrace) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("null"), nil
+	}
+
+	return t, nil
+}
+
+func (t *DebugTrace) UnmarshalJSON(data []byte) error {
+	if t == nil {
+		return errors.New("eth.DebugTrace: UnmarshalJSON on nil pointer")
+	}
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	*t = append((*t)[0:0], data...)
+
+	return nil
+}
+
+// CallFrame decodes trace produced by CallTracer.
+func (t DebugTrace) CallFrame() (*CallFrame, error) {
+	v := &CallFrame{}
+	err := json.Unmarshal(t, v)
+
+	return v, err
+}
+
+// Prestate decodes trace produced by PrestateTracer.
+func (t DebugTrace) Prestate() (Prestate, error) {
+	var v Prestate
+	err := json.Unmarshal(t, &v)
+
+	return v, err
+}
+
+// PrestateDiff decodes trace produced by PrestateTracer in diff mode.
+func (t DebugTrace) PrestateDiff() (*PrestateDiff, error) {
+	v := &PrestateDiff{}
+	err := json.Unmarshal(t, v)
+
+	return v, err
+}
+
+// StructLogs decodes trace produced by default struct logger.
+func (t DebugTrace) StructLogs() (*StructLogTrace, error) {
+	v := &StructLogTrace{}
+	err := json.Unmarshal(t, v)
+
+	return v, err
+}
+
+// CallFrame is call frame produced by CallTracer.
+type CallFrame struct {
+	Type         string      `json:"type"`
+	From         string      `json:"from"`
+	To           string      `json:"to"`
+	Value        *big.Int    `json:"value"`
+	Gas          *big.Int    `json:"gas"`
+	GasUsed      *big.Int    `json:"gasUsed"`
+	Input        string      `json:"input"`
+	Output       string      `json:"output"`
+	Error        string      `json:"error"`
+	RevertReason string      `json:"revertReason"`
+	Calls        []CallFrame `json:"calls"`
+	Logs         []CallLog   `json:"logs"`
+}
+
+func (f *CallFrame) UnmarshalJSON(data []byte) error {
+	type alias CallFrame
+
+	aux := &struct {
+		Value   quantity `json:"value"`
+		Gas     quantity `json:"gas"`
+		GasUsed quantity `json:"gasUsed"`
+		*alias
+	}{
+		alias: (*alias)(f),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	f.Value = aux.Value.int()
+	f.Gas = aux.Gas.int()
+	f.GasUsed = aux.GasUsed.int()
+
+	return nil
+}
+
+// CallLog is log emitted by call frame. Position is index of log among nested calls of the frame.
+type CallLog struct {
+	Address  string   `json:"address"`
+	Topics   []string `json:"topics"`
+	Data     string   `json:"data"`
+	Position *big.Int `json:"position"`
+}
+
+func (l *CallLog) UnmarshalJSON(data []byte) error {
+	type alias CallLog
+
+	aux := &struct {
+		Position quantity `json:"position"`
+		*alias
+	}{
+		alias: (*alias)(l),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	l.Position = aux.Position.int()
+
+	return nil
+}
+
+// Prestate is accounts state keyed by address produced by PrestateTracer.
+type Prestate map[string]PrestateAccount
+
+// PrestateDiff is accounts state before and after transaction produced by PrestateTracer in diff mode.
+type PrestateDiff struct {
+	Pre  Prestate `json:"pre"`
+	Post Prestate `json:"post"`
+}
+
+// PrestateAccount is account state produced by PrestateTracer.
+type PrestateAccount struct {
+	Balance *big.Int          `json:"balance"`
+	Nonce   uint64            `json:"nonce"`
+	Code    string            `json:"code"`
+	Storage map[string]string `json:"storage"`
+}
+
+func (a *PrestateAccount) UnmarshalJSON(data []byte) error {
+	type alias PrestateAccount
+
+	aux := &struct {
+		Balance quantity `json:"balance"`
+		*alias
+	}{
+		alias: (*alias)(a),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Balance != "" {
+		a.Balance = aux.Balance.int()
+	}
+
+	return nil
+}
+
+// StructLogTrace is trace produced by default struct logger.
+type StructLogTrace struct {
+	Gas         uint64      `json:"gas"`
+	Failed      bool        `json:"failed"`
+	ReturnValue string      `json:"returnValue"`
+	StructLogs  []StructLog `json:"structLogs"`
+}
+
+// StructLog is single executed opcode.
+type StructLog struct {
+	PC      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error"`
+	Stack   []string          `json:"stack"`
+	Memory  []string          `json:"memory"`
+	Storage map[string]string `json:"storage"`
+	Refund  uint64            `json:"refund"`
+}
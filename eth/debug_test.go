@@ -0,0 +1,159 @@
+package eth_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ofen/getblock-go/eth"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestDebugTraceCallFrame(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("debug_traceTransaction", json.RawMessage(`{
+		"type": "CALL", "from": "0x1", "to": "0x2", "value": "0x1", "gas": "0x7530", "gasUsed": "0x5208", "input": "0x",
+		"calls": [
+			{"type": "DELEGATECALL", "from": "0x2", "to": "0x3", "gas": "0x100", "gasUsed": "0x10", "error": "execution reverted", "revertReason": "nope"}
+		],
+		"logs": [{"address": "0x2", "topics": ["0xaa"], "data": "0x", "position": "0x1"}]
+	}`))
+
+	c := eth.NewClient(s.GetblockClient())
+	trace, err := c.DebugTraceTransaction(context.Background(), "0xa", &eth.TraceConfig{
+		Tracer:       eth.CallTracer,
+		TracerConfig: eth.CallTracerConfig{WithLog: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg map[string]interface{}
+	if err := s.Calls("debug_traceTransaction")[0].Param(1, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg["tracer"] != "callTracer" || cfg["tracerConfig"].(map[string]interface{})["withLog"] != true {
+		t.Errorf("config = %v", cfg)
+	}
+
+	frame, err := trace.CallFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frame.Type != "CALL" || frame.Value.Int64() != 1 || frame.Gas.Int64() != 30000 || frame.GasUsed.Int64() != 21000 {
+		t.Errorf("frame = %+v", frame)
+	}
+
+	if len(frame.Calls) != 1 || frame.Calls[0].RevertReason != "nope" || frame.Calls[0].Value.Sign() != 0 {
+		t.Errorf("calls = %+v", frame.Calls)
+	}
+
+	if len(frame.Logs) != 1 || frame.Logs[0].Position.Int64() != 1 {
+		t.Errorf("logs = %+v", frame.Logs)
+	}
+}
+
+func TestDebugTracePrestate(t *testing.T) {
+	trace := eth.DebugTrace(`{
+		"0x1": {"balance": "0xde0b6b3a7640000", "nonce": 3, "storage": {"0x0": "0x1"}},
+		"0x2": {"code": "0x60"}
+	}`)
+
+	state, err := trace.Prestate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a := state["0x1"]; a.Balance.Cmp(big.NewInt(1e18)) != 0 || a.Nonce != 3 || a.Storage["0x0"] != "0x1" {
+		t.Errorf("account 0x1 = %+v", a)
+	}
+
+	// accounts with omitted balance have nil balance
+	if a := state["0x2"]; a.Balance != nil || a.Code != "0x60" {
+		t.Errorf("account 0x2 = %+v", a)
+	}
+
+	diff, err := eth.DebugTrace(`{"pre": {"0x1": {"balance": "0x2"}}, "post": {"0x1": {"balance": "0x1", "nonce": 1}}}`).PrestateDiff()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff.Pre["0x1"].Balance.Int64() != 2 || diff.Post["0x1"].Balance.Int64() != 1 || diff.Post["0x1"].Nonce != 1 {
+		t.Errorf("diff = %+v", diff)
+	}
+}
+
+func TestDebugTraceStructLogs(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("debug_traceBlockByNumber", json.RawMessage(`[
+		{"txHash": "0xa", "result": {"gas": 21000, "failed": false, "returnValue": "", "structLogs": [
+			{"pc": 0, "op": "PUSH1", "gas": 100, "gasCost": 3, "depth": 1, "stack": []},
+			{"pc": 2, "op": "SSTORE", "gas": 97, "gasCost": 20000, "depth": 1, "stack": ["0x1", "0x0"], "storage": {"0x0": "0x1"}, "error": "out of gas"}
+		]}},
+		{"txHash": "0xb", "error": "execution timeout"}
+	]`))
+
+	traces, err := eth.NewClient(s.GetblockClient()).DebugTraceBlockByNumber(context.Background(), big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(traces) != 2 || traces[1].Error != "execution timeout" || traces[1].Result != nil {
+		t.Fatalf("traces = %+v", traces)
+	}
+
+	logs, err := traces[0].Result.StructLogs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if logs.Gas != 21000 || len(logs.StructLogs) != 2 {
+		t.Fatalf("trace = %+v", logs)
+	}
+
+	if l := logs.StructLogs[1]; l.Op != "SSTORE" || l.GasCost != 20000 || l.Error != "out of gas" || l.Storage["0x0"] != "0x1" {
+		t.Errorf("struct log = %+v", l)
+	}
+}
+
+// TestDebugTraceMalformed checks that malformed quantities are reported instead of panicking.
+func TestDebugTraceMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		trace  eth.DebugTrace
+		decode func(eth.DebugTrace) error
+	}{
+		{
+			name:   "call frame value",
+			trace:  eth.DebugTrace(`{"type": "CALL", "value": "0xzz"}`),
+			decode: func(t eth.DebugTrace) error { _, err := t.CallFrame(); return err },
+		},
+		{
+			name:   "nested call frame gas",
+			trace:  eth.DebugTrace(`{"type": "CALL", "calls": [{"gas": "gas"}]}`),
+			decode: func(t eth.DebugTrace) error { _, err := t.CallFrame(); return err },
+		},
+		{
+			name:   "log position",
+			trace:  eth.DebugTrace(`{"type": "CALL", "logs": [{"position": "-"}]}`),
+			decode: func(t eth.DebugTrace) error { _, err := t.CallFrame(); return err },
+		},
+		{
+			name:   "prestate balance",
+			trace:  eth.DebugTrace(`{"0x1": {"balance": "0xg"}}`),
+			decode: func(t eth.DebugTrace) error { _, err := t.Prestate(); return err },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.decode(tt.trace); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
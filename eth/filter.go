@@ -0,0 +1,267 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ofen/getblock-go"
+)
+
+// defaultPollInterval is interval of filter polling of subscriptions.
+const defaultPollInterval = 4 * time.Second
+
+// FilterQuery is filter options of eth_getLogs and eth_newFilter. Nil FromBlock and ToBlock mean latest block,
+// they are ignored if BlockHash is set. Topics are matched by position, empty position matches any topic.
+type FilterQuery struct {
+	BlockHash string
+	FromBlock *big.Int
+	ToBlock   *big.Int
+	Addresses []string
+	Topics    [][]string
+}
+
+func (q FilterQuery) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		BlockHash string        `json:"blockHash,omitempty"`
+		FromBlock string        `json:"fromBlock,omitempty"`
+		ToBlock   string        `json:"toBlock,omitempty"`
+		Address   []string      `json:"address,omitempty"`
+		Topics    []interface{} `json:"topics,omitempty"`
+	}{
+		BlockHash: q.BlockHash,
+		Address:   q.Addresses,
+	}
+
+	if q.BlockHash == "" {
+		aux.FromBlock = blockNumberArg(q.FromBlock)
+		aux.ToBlock = blockNumberArg(q.ToBlock)
+	}
+
+	for _, topics := range q.Topics {
+		if len(topics) == 0 {
+			aux.Topics = append(aux.Topics, nil)
+			continue
+		}
+
+		aux.Topics = append(aux.Topics, topics)
+	}
+
+	return json.Marshal(aux)
+}
+
+// FilterChanges is raw result of eth_getFilterChanges. Its format depends on filter type:
+// log filters return logs, block and pending transaction filters return hashes.
+type FilterChanges []byte
+
+func (f FilterChanges) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("null"), nil
+	}
+
+	return f, nil
+}
+
+func (f *FilterChanges) UnmarshalJSON(data []byte) error {
+	if f == nil {
+		return errors.New("eth.FilterChanges: UnmarshalJSON on nil pointer")
+	}
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	*f = append((*f)[0:0], data...)
+
+	return nil
+}
+
+// Logs decodes changes of log filter.
+func (f FilterChanges) Logs() ([]Log, error) {
+	var v []Log
+	err := json.Unmarshal(f, &v)
+
+	return v, err
+}
+
+// Hashes decodes changes of block or pending transaction filter.
+func (f FilterChanges) Hashes() ([]string, error) {
+	var v []string
+	err := json.Unmarshal(f, &v)
+
+	return v, err
+}
+
+// Subscription is stream of events delivered to channel.
+type Subscription interface {
+	// Unsubscribe stops delivery of events and closes error channel.
+	Unsubscribe()
+	// Err returns channel receiving subscription error. Channel is closed on Unsubscribe.
+	Err() <-chan error
+}
+
+// SubscribeNewHeads delivers new blocks without detailed transactions to ch until ctx is done or
+// subscription is canceled. New blocks are polled with block filter every PollInterval.
+// If client has several endpoints (see getblock.NewPool), filter is created and polled on single endpoint.
+func (c *Client) SubscribeNewHeads(ctx context.Context, ch chan<- *Block) (Subscription, error) {
+	ctx, id, err := c.newPinnedFilter(ctx, c.NewBlockFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.subscribe(ctx, id, func(ctx context.Context, changes FilterChanges) error {
+		hashes, err := changes.Hashes()
+		if err != nil {
+			return err
+		}
+
+		for _, hash := range hashes {
+			block, err := c.GetBlockByHash(ctx, hash, false)
+			if err != nil {
+				return err
+			}
+
+			if block == nil {
+				continue
+			}
+
+			select {
+			case ch <- block:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+// SubscribeLogs delivers logs matching q to ch until ctx is done or subscription is canceled.
+// Logs are polled with log filter every PollInterval. If client has several endpoints (see getblock.NewPool),
+// filter is created and polled on single endpoint.
+func (c *Client) SubscribeLogs(ctx context.Context, q FilterQuery, ch chan<- Log) (Subscription, error) {
+	ctx, id, err := c.newPinnedFilter(ctx, func(ctx context.Context) (string, error) {
+		return c.NewFilter(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.subscribe(ctx, id, func(ctx context.Context, changes FilterChanges) error {
+		logs, err := changes.Logs()
+		if err != nil {
+			return err
+		}
+
+		for _, l := range logs {
+			select {
+			case ch <- l:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+// pollSubscription is Subscription polling filter changes.
+type pollSubscription struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    chan error
+}
+
+func (s *pollSubscription) Unsubscribe() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *pollSubscription) Err() <-chan error {
+	return s.err
+}
+
+// newPinnedFilter creates filter with newFilter and returns context sending calls to endpoint which
+// created it, as filters exist only on that node. Endpoints of client are tried in order.
+func (c *Client) newPinnedFilter(ctx context.Context, newFilter func(context.Context) (string, error)) (context.Context, string, error) {
+	var endpoints []string
+	if l, ok := c.Client.(endpointLister); ok {
+		endpoints = l.Endpoints()
+	}
+
+	if len(endpoints) < 2 {
+		id, err := newFilter(ctx)
+		return ctx, id, err
+	}
+
+	var err error
+	for _, endpoint := range endpoints {
+		pinned := getblock.WithEndpoint(ctx, endpoint)
+
+		var id string
+		if id, err = newFilter(pinned); err == nil {
+			return pinned, id, nil
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, "", err
+}
+
+// subscribe polls changes of filter with id and passes them to deliver. Filter is uninstalled
+// when subscription ends.
+func (c *Client) subscribe(ctx context.Context, id string, deliver func(context.Context, FilterChanges) error) Subscription {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &pollSubscription{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		err:    make(chan error, 1),
+	}
+
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	go func() {
+		defer close(s.done)
+		defer close(s.err)
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interval)
+			defer cancel()
+
+			c.UninstallFilter(ctx, id)
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			changes, err := c.GetFilterChanges(ctx, id)
+			if err == nil {
+				err = deliver(ctx, changes)
+			}
+
+			if err != nil {
+				if ctx.Err() == nil {
+					s.err <- err
+				}
+
+				return
+			}
+		}
+	}()
+
+	return s
+}
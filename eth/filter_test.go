@@ -0,0 +1,184 @@
+package eth_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/eth"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+const (
+	alice = "0x00000000000000000000000000000000000a11ce"
+	bob   = "0x0000000000000000000000000000000000000b0b"
+	topic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+)
+
+func TestSubscribeNewHeads(t *testing.T) {
+	b := getblocktest.NewBackend(nil)
+	defer b.Close()
+
+	c := eth.NewClient(b.GetblockClient())
+	c.PollInterval = 10 * time.Millisecond
+
+	ch := make(chan *eth.Block)
+	sub, err := c.SubscribeNewHeads(context.Background(), ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		hash := b.Commit()
+
+		select {
+		case block := <-ch:
+			if block.Hash != hash || block.Number.Int64() != int64(i) {
+				t.Errorf("block = %s %v, want %s %d", block.Hash, block.Number, hash, i)
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(time.Second):
+			t.Fatalf("block %d is not delivered", i)
+		}
+	}
+
+	sub.Unsubscribe()
+
+	if _, ok := <-sub.Err(); ok {
+		t.Error("error channel is not closed")
+	}
+
+	if calls := b.Calls("eth_uninstallFilter"); len(calls) != 1 {
+		t.Errorf("got %d eth_uninstallFilter calls, want 1", len(calls))
+	}
+}
+
+func TestSubscribeLogs(t *testing.T) {
+	b := getblocktest.NewBackend(map[string]*big.Int{alice: big.NewInt(eth.Ether)})
+	defer b.Close()
+
+	c := eth.NewClient(b.GetblockClient())
+	c.PollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan eth.Log)
+	sub, err := c.SubscribeLogs(ctx, eth.FilterQuery{Addresses: []string{bob}}, ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, address := range []string{alice, bob} {
+		hash, err := b.Transfer(alice, bob, big.NewInt(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := b.AddLog(hash, address, []string{topic}, "0x01"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Commit()
+
+	select {
+	case l := <-ch:
+		if l.Address != bob || len(l.Topics) != 1 || l.Topics[0] != topic || l.BlockNumber.Int64() != 1 {
+			t.Errorf("log = %+v, want log of %s", l, bob)
+		}
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("log is not delivered")
+	}
+
+	select {
+	case l := <-ch:
+		t.Errorf("unexpected log %+v", l)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// subscription ends with its context
+	cancel()
+	if _, ok := <-sub.Err(); ok {
+		t.Error("error channel is not closed")
+	}
+}
+
+func TestSubscriptionError(t *testing.T) {
+	b := getblocktest.NewBackend(nil)
+	defer b.Close()
+
+	c := eth.NewClient(b.GetblockClient())
+	c.PollInterval = 10 * time.Millisecond
+
+	sub, err := c.SubscribeNewHeads(context.Background(), make(chan *eth.Block))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	// filter expired on node
+	b.RespondError("eth_getFilterChanges", getblocktest.CodeServerError, "filter not found")
+
+	select {
+	case err := <-sub.Err():
+		if err == nil {
+			t.Error("error channel is closed without error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("error is not delivered")
+	}
+}
+
+func TestSubscribePool(t *testing.T) {
+	first, second := getblocktest.NewBackend(nil), getblocktest.NewBackend(nil)
+	defer first.Close()
+	defer second.Close()
+
+	// first endpoint does not support filters, subscription is pinned to second one
+	first.RespondError("eth_newBlockFilter", getblocktest.CodeMethodNotFound, "the method eth_newBlockFilter does not exist/is not available")
+
+	pool := getblock.NewPool([]getblock.Endpoint{
+		{URL: first.URL, Token: getblocktest.Token},
+		{URL: second.URL, Token: getblocktest.Token},
+	})
+	defer pool.Close()
+
+	c := eth.NewClient(pool)
+	c.PollInterval = 10 * time.Millisecond
+
+	ch := make(chan *eth.Block)
+	sub, err := c.SubscribeNewHeads(context.Background(), ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		hash := second.Commit()
+
+		select {
+		case block := <-ch:
+			if block.Hash != hash {
+				t.Errorf("block = %s, want %s", block.Hash, hash)
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(time.Second):
+			t.Fatalf("block %d is not delivered", i)
+		}
+	}
+
+	sub.Unsubscribe()
+
+	for _, method := range []string{"eth_getFilterChanges", "eth_getBlockByHash", "eth_uninstallFilter"} {
+		if calls := first.Calls(method); len(calls) != 0 {
+			t.Errorf("first endpoint got %d %s calls, want 0", len(calls), method)
+		}
+
+		if calls := second.Calls(method); len(calls) == 0 {
+			t.Errorf("second endpoint got no %s calls", method)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+)
+
+// BlockReader reads blocks, transactions and receipts.
+type BlockReader interface {
+	BlockNumber(ctx context.Context) (*big.Int, error)
+	GetBlockByNumber(ctx context.Context, blockNumber *big.Int, detailedTransactions bool) (*Block, error)
+	GetBlockByHash(ctx context.Context, hash string, detailedTransactions bool) (*Block, error)
+	GetTransactionByHash(ctx context.Context, hash string) (*Transaction, error)
+	GetTransactionReceipt(ctx context.Context, hash string) (*Receipt, error)
+}
+
+// StateReader reads accounts state at block, nil block number means latest block.
+type StateReader interface {
+	GetBalance(ctx context.Context, address string, blockNumber *big.Int) (*big.Int, error)
+	GetTransactionCount(ctx context.Context, address string, blockNumber *big.Int) (*big.Int, error)
+	GetCode(ctx context.Context, address string, blockNumber *big.Int) (string, error)
+	GetStorageAt(ctx context.Context, address string, position string, blockNumber *big.Int) (string, error)
+	Call(ctx context.Context, msg CallMsg, blockNumber *big.Int) (string, error)
+}
+
+// TxSender sends signed transactions.
+type TxSender interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	SendRawTransaction(ctx context.Context, data string) (string, error)
+	EstimateGas(ctx context.Context, msg CallMsg) (*big.Int, error)
+	GasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// LogFilterer queries logs.
+type LogFilterer interface {
+	GetLogs(ctx context.Context, q FilterQuery) ([]Log, error)
+	NewFilter(ctx context.Context, q FilterQuery) (string, error)
+	GetFilterChanges(ctx context.Context, id string) (FilterChanges, error)
+	GetFilterLogs(ctx context.Context, id string) ([]Log, error)
+	UninstallFilter(ctx context.Context, id string) (bool, error)
+}
+
+// Subscriber subscribes to new blocks and logs.
+type Subscriber interface {
+	SubscribeNewHeads(ctx context.Context, ch chan<- *Block) (Subscription, error)
+	SubscribeLogs(ctx context.Context, q FilterQuery, ch chan<- Log) (Subscription, error)
+}
+
+var (
+	_ BlockReader = (*Client)(nil)
+	_ StateReader = (*Client)(nil)
+	_ TxSender    = (*Client)(nil)
+	_ LogFilterer = (*Client)(nil)
+	_ Subscriber  = (*Client)(nil)
+)
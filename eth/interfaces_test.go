@@ -0,0 +1,109 @@
+package eth_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/eth"
+)
+
+// fakeCaller answers calls with canned results and records them.
+type fakeCaller struct {
+	results map[string]string
+	calls   []string
+	params  [][]interface{}
+}
+
+func (f *fakeCaller) Call(ctx context.Context, method string, params ...interface{}) (*getblock.Response, error) {
+	f.calls = append(f.calls, method)
+	f.params = append(f.params, params)
+
+	result, ok := f.results[method]
+	if !ok {
+		return &getblock.Response{Error: &getblock.RPCError{Code: -32601, Message: "method not found"}}, nil
+	}
+
+	return &getblock.Response{Result: json.RawMessage(result)}, nil
+}
+
+// cachedBlockNumber is BlockReader decorating another one.
+type cachedBlockNumber struct {
+	eth.BlockReader
+	number *big.Int
+}
+
+func (c *cachedBlockNumber) BlockNumber(ctx context.Context) (*big.Int, error) {
+	if c.number == nil {
+		n, err := c.BlockReader.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.number = n
+	}
+
+	return c.number, nil
+}
+
+func TestClientInterfaces(t *testing.T) {
+	f := &fakeCaller{results: map[string]string{
+		"eth_blockNumber":        `"0x10"`,
+		"eth_getBalance":         `"0x3e8"`,
+		"eth_sendRawTransaction": `"0xabc"`,
+		"eth_getLogs":            `[{"address": "0x1", "logIndex": "0x2"}]`,
+	}}
+	c := eth.NewClient(f)
+	ctx := context.Background()
+
+	var blocks eth.BlockReader = &cachedBlockNumber{BlockReader: c}
+	for i := 0; i < 2; i++ {
+		n, err := blocks.BlockNumber(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n.Int64() != 16 {
+			t.Errorf("block number = %v, want 16", n)
+		}
+	}
+
+	var state eth.StateReader = c
+	balance, err := state.GetBalance(ctx, "0x1", big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Int64() != 1000 {
+		t.Errorf("balance = %v, want 1000", balance)
+	}
+
+	var sender eth.TxSender = c
+	if hash, err := sender.SendRawTransaction(ctx, "0xf86c"); err != nil || hash != "0xabc" {
+		t.Errorf("hash = %s, error %v, want 0xabc", hash, err)
+	}
+
+	var filterer eth.LogFilterer = c
+	logs, err := filterer.GetLogs(ctx, eth.FilterQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].LogIndex.Int64() != 2 {
+		t.Errorf("logs = %+v", logs)
+	}
+
+	if _, err := sender.GasPrice(ctx); err == nil {
+		t.Error("expected error of method without result")
+	}
+
+	want := []string{"eth_blockNumber", "eth_getBalance", "eth_sendRawTransaction", "eth_getLogs", "eth_gasPrice"}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Errorf("calls = %v, want %v", f.calls, want)
+	}
+
+	if !reflect.DeepEqual(f.params[1], []interface{}{"0x1", "0x5"}) {
+		t.Errorf("eth_getBalance params = %v", f.params[1])
+	}
+}
@@ -0,0 +1,230 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ofen/getblock-go/internal/rlp"
+)
+
+var (
+	// ErrInvalidProof is returned when proof does not match trusted state root.
+	ErrInvalidProof = errors.New("invalid proof")
+
+	// emptyRootHash is root hash of empty trie.
+	emptyRootHash = mustDecodeHex("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	// emptyCodeHash is hash of empty code.
+	emptyCodeHash = mustDecodeHex("0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470")
+)
+
+// GetProof returns the account and storage values of the specified account, including the Merkle proof.
+// Nil blockNumber means latest block.
+//
+// The API allows IoT devices or mobile apps which are unable to run light clients to verify responses from untrusted sources, by using a trusted block hash.
+// Use AccountProof.Verify to check returned values against StateRoot of trusted block.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/eth_getProof
+func (c *Client) GetProof(ctx context.Context, address string, storageKeys []string, blockNumber *big.Int) (*AccountProof, error) {
+	if storageKeys == nil {
+		storageKeys = []string{}
+	}
+
+	return call[*AccountProof](ctx, c, "eth_getProof", address, storageKeys, blockNumberArg(blockNumber))
+}
+
+// AccountProof is account state with Merkle proofs.
+type AccountProof struct {
+	Address      string         `json:"address"`
+	AccountProof []string       `json:"accountProof"`
+	Balance      *big.Int       `json:"balance"`
+	CodeHash     string         `json:"codeHash"`
+	Nonce        *big.Int       `json:"nonce"`
+	StorageHash  string         `json:"storageHash"`
+	StorageProof []StorageProof `json:"storageProof"`
+}
+
+func (p *AccountProof) UnmarshalJSON(data []byte) error {
+	type alias AccountProof
+
+	aux := &struct {
+		Balance quantity `json:"balance"`
+		Nonce   quantity `json:"nonce"`
+		*alias
+	}{
+		alias: (*alias)(p),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.Balance = aux.Balance.int()
+	p.Nonce = aux.Nonce.int()
+
+	return nil
+}
+
+// StorageProof is storage slot value with Merkle proof.
+type StorageProof struct {
+	Key   string   `json:"key"`
+	Value *big.Int `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+func (p *StorageProof) UnmarshalJSON(data []byte) error {
+	type alias StorageProof
+
+	aux := &struct {
+		Value quantity `json:"value"`
+		*alias
+	}{
+		alias: (*alias)(p),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.Value = aux.Value.int()
+
+	return nil
+}
+
+// Verify checks account balance, nonce, code hash, storage hash and all storage values
+// against trusted state root (e.g. Block.StateRoot). Returned error wraps ErrInvalidProof
+// if any value does not match the proof.
+func (p *AccountProof) Verify(stateRoot string) error {
+	root, err := decodeHex(stateRoot)
+	if err != nil {
+		return fmt.Errorf("state root: %w", err)
+	}
+
+	address, err := decodeHex(p.Address)
+	if err != nil {
+		return fmt.Errorf("address: %w", err)
+	}
+
+	proof, err := decodeHexList(p.AccountProof)
+	if err != nil {
+		return fmt.Errorf("account proof: %w", err)
+	}
+
+	storageHash, err := decodeHex(p.StorageHash)
+	if err != nil {
+		return fmt.Errorf("storage hash: %w", err)
+	}
+
+	codeHash, err := decodeHex(p.CodeHash)
+	if err != nil {
+		return fmt.Errorf("code hash: %w", err)
+	}
+
+	value, err := verifyTrieProof(root, keccak256(address), proof)
+	if err != nil {
+		return fmt.Errorf("%w: account %s: %v", ErrInvalidProof, p.Address, err)
+	}
+
+	// Account absent from the trie is empty account.
+	nonce, balance, wantStorageHash, wantCodeHash := new(big.Int), new(big.Int), emptyRootHash, emptyCodeHash
+	if value != nil {
+		account, err := rlp.Decode(value)
+		if err != nil || !account.IsList || len(account.List) != 4 {
+			return fmt.Errorf("%w: account %s: invalid account encoding", ErrInvalidProof, p.Address)
+		}
+
+		nonce.SetBytes(account.List[0].Data)
+		balance.SetBytes(account.List[1].Data)
+		wantStorageHash, wantCodeHash = account.List[2].Data, account.List[3].Data
+	}
+
+	switch {
+	case p.Nonce == nil || p.Nonce.Cmp(nonce) != 0:
+		return fmt.Errorf("%w: account %s: nonce %v, proven %v", ErrInvalidProof, p.Address, p.Nonce, nonce)
+	case p.Balance == nil || p.Balance.Cmp(balance) != 0:
+		return fmt.Errorf("%w: account %s: balance %v, proven %v", ErrInvalidProof, p.Address, p.Balance, balance)
+	case !bytes.Equal(storageHash, wantStorageHash):
+		return fmt.Errorf("%w: account %s: storage hash %s, proven %#x", ErrInvalidProof, p.Address, p.StorageHash, wantStorageHash)
+	case !bytes.Equal(codeHash, wantCodeHash):
+		return fmt.Errorf("%w: account %s: code hash %s, proven %#x", ErrInvalidProof, p.Address, p.CodeHash, wantCodeHash)
+	}
+
+	for _, sp := range p.StorageProof {
+		if err := sp.verify(storageHash); err != nil {
+			return fmt.Errorf("%w: account %s: storage key %s: %v", ErrInvalidProof, p.Address, sp.Key, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *StorageProof) verify(storageHash []byte) error {
+	key, ok := new(big.Int).SetString(p.Key, 0)
+	if !ok || key.Sign() < 0 || key.BitLen() > 256 {
+		return fmt.Errorf("invalid key")
+	}
+
+	proof, err := decodeHexList(p.Proof)
+	if err != nil {
+		return err
+	}
+
+	slot := make([]byte, 32)
+	key.FillBytes(slot)
+
+	value, err := verifyTrieProof(storageHash, keccak256(slot), proof)
+	if err != nil {
+		return err
+	}
+
+	proven := new(big.Int)
+	if value != nil {
+		item, err := rlp.Decode(value)
+		if err != nil || item.IsList {
+			return fmt.Errorf("invalid value encoding")
+		}
+		proven.SetBytes(item.Data)
+	}
+
+	if p.Value == nil || p.Value.Cmp(proven) != 0 {
+		return fmt.Errorf("value %v, proven %v", p.Value, proven)
+	}
+
+	return nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 != 0 {
+		s = "0" + s
+	}
+
+	return hex.DecodeString(s)
+}
+
+func decodeHexList(list []string) ([][]byte, error) {
+	out := make([][]byte, len(list))
+	for i, s := range list {
+		b, err := decodeHex(s)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = b
+	}
+
+	return out, nil
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := decodeHex(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
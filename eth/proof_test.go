@@ -0,0 +1,216 @@
+package eth_test
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ofen/getblock-go/eth"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+const (
+	emptyRoot     = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+	emptyCodeHash = "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
+)
+
+func TestProofVerify(t *testing.T) {
+	address := "0x00000000000000000000000000000000000a11ce"
+	stateRoot, proof := accountProof(address, big.NewInt(5), big.NewInt(1e18), 0x2a)
+
+	tests := []struct {
+		name string
+		// modify changes eth_getProof response of valid proof.
+		modify    func(p map[string]interface{})
+		stateRoot string
+		// wantDecodeErr means response fails to decode.
+		wantDecodeErr bool
+		wantErr       error
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name:    "wrong balance",
+			modify:  func(p map[string]interface{}) { p["balance"] = "0x1" },
+			wantErr: eth.ErrInvalidProof,
+		},
+		{
+			name:    "wrong nonce",
+			modify:  func(p map[string]interface{}) { p["nonce"] = "0x6" },
+			wantErr: eth.ErrInvalidProof,
+		},
+		{
+			name:    "wrong code hash",
+			modify:  func(p map[string]interface{}) { p["codeHash"] = emptyRoot },
+			wantErr: eth.ErrInvalidProof,
+		},
+		{
+			name:    "wrong storage value",
+			modify:  func(p map[string]interface{}) { storageProof(p)["value"] = "0x2b" },
+			wantErr: eth.ErrInvalidProof,
+		},
+		{
+			name:      "untrusted state root",
+			stateRoot: emptyRoot,
+			wantErr:   eth.ErrInvalidProof,
+		},
+		{
+			name:    "missing proof node",
+			modify:  func(p map[string]interface{}) { p["accountProof"] = []string{} },
+			wantErr: eth.ErrInvalidProof,
+		},
+		{
+			name:          "malformed quantity",
+			modify:        func(p map[string]interface{}) { p["balance"] = "0xzz" },
+			wantDecodeErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := proof()
+			if tt.modify != nil {
+				tt.modify(p)
+			}
+
+			root := stateRoot
+			if tt.stateRoot != "" {
+				root = tt.stateRoot
+			}
+
+			s := getblocktest.NewServer()
+			defer s.Close()
+			s.Respond("eth_getProof", p)
+
+			c := eth.NewClient(s.GetblockClient())
+			got, err := c.GetProof(context.Background(), address, []string{"0x0"}, big.NewInt(1))
+			if tt.wantDecodeErr {
+				if err == nil {
+					t.Fatal("expected decode error")
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = got.Verify(root)
+			if tt.wantErr == nil && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProofVerifyAbsentAccount(t *testing.T) {
+	p := &eth.AccountProof{
+		Address:     "0x00000000000000000000000000000000000a11ce",
+		Balance:     new(big.Int),
+		Nonce:       new(big.Int),
+		CodeHash:    emptyCodeHash,
+		StorageHash: emptyRoot,
+	}
+
+	if err := p.Verify(emptyRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Balance = big.NewInt(1)
+	if err := p.Verify(emptyRoot); !errors.Is(err, eth.ErrInvalidProof) {
+		t.Fatalf("error = %v, want %v", err, eth.ErrInvalidProof)
+	}
+}
+
+// accountProof returns state root of trie with single account having storage slot 0 set to value,
+// and function returning new eth_getProof response of the account.
+func accountProof(address string, nonce, balance *big.Int, value int64) (string, func() map[string]interface{}) {
+	slot := make([]byte, 32)
+	storageLeaf := leafNode(keccak(slot), rlpBytes(big.NewInt(value).Bytes()))
+	storageRoot := keccak(storageLeaf)
+
+	account := rlpList(rlpBytes(nonce.Bytes()), rlpBytes(balance.Bytes()), rlpBytes(storageRoot), rlpBytes(decode(emptyCodeHash)))
+	accountLeaf := leafNode(keccak(decode(address)), account)
+
+	return encode(keccak(accountLeaf)), func() map[string]interface{} {
+		return map[string]interface{}{
+			"address":      address,
+			"accountProof": []string{encode(accountLeaf)},
+			"balance":      "0x" + balance.Text(16),
+			"codeHash":     emptyCodeHash,
+			"nonce":        "0x" + nonce.Text(16),
+			"storageHash":  encode(storageRoot),
+			"storageProof": []interface{}{
+				map[string]interface{}{
+					"key":   "0x0",
+					"value": "0x" + big.NewInt(value).Text(16),
+					"proof": []string{encode(storageLeaf)},
+				},
+			},
+		}
+	}
+}
+
+func storageProof(p map[string]interface{}) map[string]interface{} {
+	return p["storageProof"].([]interface{})[0].(map[string]interface{})
+}
+
+// leafNode returns encoded trie leaf node with full 32 byte key path.
+func leafNode(key, value []byte) []byte {
+	return rlpList(rlpBytes(append([]byte{0x20}, key...)), rlpBytes(value))
+}
+
+func rlpBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return b
+	}
+
+	return append(rlpHeader(0x80, len(b)), b...)
+}
+
+func rlpList(items ...[]byte) []byte {
+	var content []byte
+	for _, item := range items {
+		content = append(content, item...)
+	}
+
+	return append(rlpHeader(0xc0, len(content)), content...)
+}
+
+func rlpHeader(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+
+	n := big.NewInt(int64(size)).Bytes()
+
+	return append([]byte{offset + 55 + byte(len(n))}, n...)
+}
+
+func keccak(b []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(b)
+
+	return h.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func decode(s string) []byte {
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
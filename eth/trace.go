@@ -0,0 +1,505 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// TraceType is kind of trace requested from trace_* replay methods.
+type TraceType string
+
+const (
+	// TraceTypeTrace requests transaction trace.
+	TraceTypeTrace TraceType = "trace"
+	// TraceTypeStateDiff requests state changes made by transaction.
+	TraceTypeStateDiff TraceType = "stateDiff"
+	// TraceTypeVMTrace requests full virtual machine execution trace.
+	TraceTypeVMTrace TraceType = "vmTrace"
+)
+
+// Block provides transaction processing of type trace for the specified block.
+// Nil blockNumber means latest block.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_block
+func (c *Client) Block(ctx context.Context, blockNumber *big.Int) ([]Trace, error) {
+	return call[[]Trace](ctx, c, "trace_block", blockNumberArg(blockNumber))
+}
+
+// ReplayBlockTransactions provides transaction processing tracing per block.
+// Trace of type TraceTypeTrace is requested if no traceTypes given.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_replayBlockTransactions
+func (c *Client) ReplayBlockTransactions(ctx context.Context, blockNumber *big.Int, traceTypes ...TraceType) ([]TraceResults, error) {
+	return call[[]TraceResults](ctx, c, "trace_replayBlockTransactions", blockNumberArg(blockNumber), traceTypesArg(traceTypes))
+}
+
+// Transaction provides transaction processing of type trace for the specified transaction.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_transaction
+func (c *Client) Transaction(ctx context.Context, hash string) ([]Trace, error) {
+	return call[[]Trace](ctx, c, "trace_transaction", hash)
+}
+
+// TraceFilter returns traces matching given filter.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_filter
+func (c *Client) TraceFilter(ctx context.Context, q TraceFilterQuery) ([]Trace, error) {
+	return call[[]Trace](ctx, c, "trace_filter", q)
+}
+
+// TraceGet returns trace at given trace address of the specified transaction.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_get
+func (c *Client) TraceGet(ctx context.Context, hash string, traceAddress ...int) (*Trace, error) {
+	indices := make([]string, len(traceAddress))
+	for i, idx := range traceAddress {
+		indices[i] = int2hex(big.NewInt(int64(idx)))
+	}
+
+	return call[*Trace](ctx, c, "trace_get", hash, indices)
+}
+
+// TraceCall executes new message call and returns requested traces without creating transaction.
+// Nil blockNumber means latest block. Trace of type TraceTypeTrace is requested if no traceTypes given.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/trace_call
+func (c *Client) TraceCall(ctx context.Context, msg CallMsg, blockNumber *big.Int, traceTypes ...TraceType) (*TraceResults, error) {
+	return call[*TraceResults](ctx, c, "trace_call", msg, traceTypesArg(traceTypes), blockNumberArg(blockNumber))
+}
+
+func traceTypesArg(traceTypes []TraceType) []TraceType {
+	if len(traceTypes) == 0 {
+		return []TraceType{TraceTypeTrace}
+	}
+
+	return traceTypes
+}
+
+// TraceFilterQuery is filter for trace_filter.
+type TraceFilterQuery struct {
+	FromBlock   *big.Int
+	ToBlock     *big.Int
+	FromAddress []string
+	ToAddress   []string
+	After       int
+	Count       int
+}
+
+func (q TraceFilterQuery) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		FromBlock   string   `json:"fromBlock,omitempty"`
+		ToBlock     string   `json:"toBlock,omitempty"`
+		FromAddress []string `json:"fromAddress,omitempty"`
+		ToAddress   []string `json:"toAddress,omitempty"`
+		After       int      `json:"after,omitempty"`
+		Count       int      `json:"count,omitempty"`
+	}{
+		FromBlock:   optionalHex(q.FromBlock),
+		ToBlock:     optionalHex(q.ToBlock),
+		FromAddress: q.FromAddress,
+		ToAddress:   q.ToAddress,
+		After:       q.After,
+		Count:       q.Count,
+	}
+
+	return json.Marshal(aux)
+}
+
+// Trace is trace representation.
+type Trace struct {
+	Action              TraceAction `json:"action"`
+	BlockHash           string      `json:"blockHash"`
+	BlockNumber         *big.Int    `json:"blockNumber"`
+	Error               string      `json:"error"`
+	Result              TraceResult `json:"result"`
+	Subtraces           int         `json:"subtraces"`
+	TraceAddress        []int       `json:"traceAddress"`
+	TransactionHash     string      `json:"transactionHash"`
+	TransactionPosition int         `json:"transactionPosition"`
+	Type                string      `json:"type"`
+}
+
+func (t *Trace) UnmarshalJSON(data []byte) error {
+	type alias Trace
+
+	aux := &struct {
+		Action      json.RawMessage `json:"action"`
+		BlockNumber quantity        `json:"blockNumber"`
+		Result      json.RawMessage `json:"result"`
+		*alias
+	}{
+		alias: (*alias)(t),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	t.BlockNumber = aux.BlockNumber.int()
+
+	var action TraceAction
+	var result TraceResult
+	switch t.Type {
+	case "call":
+		action, result = &CallAction{}, &CallResult{}
+	case "create":
+		action, result = &CreateAction{}, &CreateResult{}
+	case "suicide":
+		action = &SuicideAction{}
+	case "reward":
+		action = &RewardAction{}
+	default:
+		return nil
+	}
+
+	if err := json.Unmarshal(aux.Action, action); err != nil {
+		return err
+	}
+	t.Action = action
+
+	if result != nil && len(aux.Result) != 0 && string(aux.Result) != "null" {
+		if err := json.Unmarshal(aux.Result, result); err != nil {
+			return err
+		}
+		t.Result = result
+	}
+
+	return nil
+}
+
+// TraceAction is one of *CallAction, *CreateAction, *SuicideAction or *RewardAction.
+// It is nil for traces of unknown type.
+type TraceAction interface {
+	traceAction()
+}
+
+// TraceResult is one of *CallResult or *CreateResult. It is nil for failed, suicide and reward traces.
+type TraceResult interface {
+	traceResult()
+}
+
+// CallAction is action of trace of type call.
+type CallAction struct {
+	CallType string   `json:"callType"`
+	From     string   `json:"from"`
+	Gas      *big.Int `json:"gas"`
+	Input    string   `json:"input"`
+	To       string   `json:"to"`
+	Value    *big.Int `json:"value"`
+}
+
+func (*CallAction) traceAction() {}
+
+func (a *CallAction) UnmarshalJSON(data []byte) error {
+	type alias CallAction
+
+	aux := &struct {
+		Gas   quantity `json:"gas"`
+		Value quantity `json:"value"`
+		*alias
+	}{
+		alias: (*alias)(a),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.Gas = aux.Gas.int()
+	a.Value = aux.Value.int()
+
+	return nil
+}
+
+// CreateAction is action of trace of type create.
+type CreateAction struct {
+	From  string   `json:"from"`
+	Gas   *big.Int `json:"gas"`
+	Init  string   `json:"init"`
+	Value *big.Int `json:"value"`
+}
+
+func (*CreateAction) traceAction() {}
+
+func (a *CreateAction) UnmarshalJSON(data []byte) error {
+	type alias CreateAction
+
+	aux := &struct {
+		Gas   quantity `json:"gas"`
+		Value quantity `json:"value"`
+		*alias
+	}{
+		alias: (*alias)(a),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.Gas = aux.Gas.int()
+	a.Value = aux.Value.int()
+
+	return nil
+}
+
+// SuicideAction is action of trace of type suicide (selfdestruct).
+type SuicideAction struct {
+	Address       string   `json:"address"`
+	Balance       *big.Int `json:"balance"`
+	RefundAddress string   `json:"refundAddress"`
+}
+
+func (*SuicideAction) traceAction() {}
+
+func (a *SuicideAction) UnmarshalJSON(data []byte) error {
+	type alias SuicideAction
+
+	aux := &struct {
+		Balance quantity `json:"balance"`
+		*alias
+	}{
+		alias: (*alias)(a),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.Balance = aux.Balance.int()
+
+	return nil
+}
+
+// RewardAction is action of trace of type reward.
+type RewardAction struct {
+	Author     string   `json:"author"`
+	RewardType string   `json:"rewardType"`
+	Value      *big.Int `json:"value"`
+}
+
+func (*RewardAction) traceAction() {}
+
+func (a *RewardAction) UnmarshalJSON(data []byte) error {
+	type alias RewardAction
+
+	aux := &struct {
+		Value quantity `json:"value"`
+		*alias
+	}{
+		alias: (*alias)(a),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.Value = aux.Value.int()
+
+	return nil
+}
+
+// CallResult is result of trace of type call.
+type CallResult struct {
+	GasUsed *big.Int `json:"gasUsed"`
+	Output  string   `json:"output"`
+}
+
+func (*CallResult) traceResult() {}
+
+func (r *CallResult) UnmarshalJSON(data []byte) error {
+	type alias CallResult
+
+	aux := &struct {
+		GasUsed quantity `json:"gasUsed"`
+		*alias
+	}{
+		alias: (*alias)(r),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.GasUsed = aux.GasUsed.int()
+
+	return nil
+}
+
+// CreateResult is result of trace of type create.
+type CreateResult struct {
+	Address string   `json:"address"`
+	Code    string   `json:"code"`
+	GasUsed *big.Int `json:"gasUsed"`
+}
+
+func (*CreateResult) traceResult() {}
+
+func (r *CreateResult) UnmarshalJSON(data []byte) error {
+	type alias CreateResult
+
+	aux := &struct {
+		GasUsed quantity `json:"gasUsed"`
+		*alias
+	}{
+		alias: (*alias)(r),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.GasUsed = aux.GasUsed.int()
+
+	return nil
+}
+
+// TraceResults is result of transaction replay.
+type TraceResults struct {
+	Output          string    `json:"output"`
+	StateDiff       StateDiff `json:"stateDiff"`
+	Trace           []Trace   `json:"trace"`
+	VMTrace         *VMTrace  `json:"vmTrace"`
+	TransactionHash string    `json:"transactionHash"`
+}
+
+// StateDiff is state changes made by transaction keyed by account address.
+type StateDiff map[string]AccountDiff
+
+// AccountDiff is changes of a single account.
+type AccountDiff struct {
+	Balance Diff            `json:"balance"`
+	Code    Diff            `json:"code"`
+	Nonce   Diff            `json:"nonce"`
+	Storage map[string]Diff `json:"storage"`
+}
+
+// Diff is change of a single value. Kind is one of "=" (unchanged), "+" (created),
+// "-" (removed) or "*" (modified).
+type Diff struct {
+	Kind string
+	From string
+	To   string
+}
+
+func (d *Diff) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &d.Kind); err == nil {
+		return nil
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	if len(m) != 1 {
+		return fmt.Errorf("invalid diff: %s", data)
+	}
+
+	for k, v := range m {
+		d.Kind = k
+		switch k {
+		case "+":
+			return json.Unmarshal(v, &d.To)
+		case "-":
+			return json.Unmarshal(v, &d.From)
+		case "*":
+			aux := struct {
+				From string `json:"from"`
+				To   string `json:"to"`
+			}{}
+			if err := json.Unmarshal(v, &aux); err != nil {
+				return err
+			}
+			d.From, d.To = aux.From, aux.To
+		default:
+			return fmt.Errorf("unknown diff kind: %s", k)
+		}
+	}
+
+	return nil
+}
+
+// VMTrace is virtual machine execution trace.
+type VMTrace struct {
+	Code string        `json:"code"`
+	Ops  []VMOperation `json:"ops"`
+}
+
+// VMOperation is single executed instruction.
+type VMOperation struct {
+	Cost uint64      `json:"cost"`
+	Ex   *VMExecuted `json:"ex"`
+	PC   uint64      `json:"pc"`
+	Sub  *VMTrace    `json:"sub"`
+}
+
+// VMExecuted is side effects of executed instruction.
+type VMExecuted struct {
+	Mem   *VMMemory `json:"mem"`
+	Push  []string  `json:"push"`
+	Store *VMStore  `json:"store"`
+	Used  uint64    `json:"used"`
+}
+
+// VMMemory is memory write made by instruction.
+type VMMemory struct {
+	Data string `json:"data"`
+	Off  uint64 `json:"off"`
+}
+
+// VMStore is storage write made by instruction.
+type VMStore struct {
+	Key string `json:"key"`
+	Val string `json:"val"`
+}
+
+// TraceNode is trace with its nested calls.
+type TraceNode struct {
+	Trace
+	Calls []*TraceNode
+}
+
+// TraceTree reconstructs nested call trees from flat trace list as returned by trace_block,
+// trace_transaction or trace_filter. Root node is returned per transaction (and per reward)
+// in order of appearance. Traces which parent is missing from the list are returned as roots.
+func TraceTree(traces []Trace) []*TraceNode {
+	nodes := make([]*TraceNode, len(traces))
+	index := make(map[string]*TraceNode, len(traces))
+	for i := range traces {
+		nodes[i] = &TraceNode{Trace: traces[i]}
+		index[traceKey(&traces[i], traces[i].TraceAddress)] = nodes[i]
+	}
+
+	var roots []*TraceNode
+	for _, n := range nodes {
+		addr := n.TraceAddress
+		if len(addr) == 0 {
+			roots = append(roots, n)
+			continue
+		}
+
+		parent, ok := index[traceKey(&n.Trace, addr[:len(addr)-1])]
+		if !ok {
+			roots = append(roots, n)
+			continue
+		}
+
+		parent.Calls = append(parent.Calls, n)
+	}
+
+	for _, n := range nodes {
+		sort.SliceStable(n.Calls, func(i, j int) bool {
+			a, b := n.Calls[i].TraceAddress, n.Calls[j].TraceAddress
+			return a[len(a)-1] < b[len(b)-1]
+		})
+	}
+
+	return roots
+}
+
+func traceKey(t *Trace, traceAddress []int) string {
+	return fmt.Sprintf("%s:%d:%v", t.TransactionHash, t.TransactionPosition, traceAddress)
+}
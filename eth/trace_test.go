@@ -0,0 +1,213 @@
+package eth_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ofen/getblock-go/eth"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestTraceUnmarshal(t *testing.T) {
+	tests := []struct {
+		name       string
+		trace      string
+		wantAction eth.TraceAction
+		wantResult eth.TraceResult
+		wantErr    bool
+	}{
+		{
+			name:  "call",
+			trace: `{"type":"call","blockNumber":"0x10","action":{"callType":"call","from":"0x1","gas":"0x5208","input":"0x","to":"0x2","value":"0xde0b6b3a7640000"},"result":{"gasUsed":"0x0","output":"0x"},"traceAddress":[]}`,
+			wantAction: &eth.CallAction{
+				CallType: "call",
+				From:     "0x1",
+				Gas:      big.NewInt(21000),
+				Input:    "0x",
+				To:       "0x2",
+				Value:    big.NewInt(1e18),
+			},
+			wantResult: &eth.CallResult{GasUsed: big.NewInt(0), Output: "0x"},
+		},
+		{
+			name:       "create",
+			trace:      `{"type":"create","blockNumber":"0x10","action":{"from":"0x1","gas":"0x10","init":"0x60","value":"0x0"},"result":{"address":"0x3","code":"0x60","gasUsed":"0x8"}}`,
+			wantAction: &eth.CreateAction{From: "0x1", Gas: big.NewInt(16), Init: "0x60", Value: big.NewInt(0)},
+			wantResult: &eth.CreateResult{Address: "0x3", Code: "0x60", GasUsed: big.NewInt(8)},
+		},
+		{
+			name:       "suicide",
+			trace:      `{"type":"suicide","blockNumber":"0x10","action":{"address":"0x3","balance":"0x1","refundAddress":"0x1"},"result":null}`,
+			wantAction: &eth.SuicideAction{Address: "0x3", Balance: big.NewInt(1), RefundAddress: "0x1"},
+		},
+		{
+			name:       "reward",
+			trace:      `{"type":"reward","blockNumber":"0x10","action":{"author":"0x4","rewardType":"block","value":"0x1bc16d674ec80000"}}`,
+			wantAction: &eth.RewardAction{Author: "0x4", RewardType: "block", Value: big.NewInt(2e18)},
+		},
+		{
+			name:  "failed call",
+			trace: `{"type":"call","blockNumber":"0x10","action":{"callType":"call","from":"0x1","gas":"0x0","input":"0x","to":"0x2","value":"0x0"},"error":"Reverted","result":null}`,
+			wantAction: &eth.CallAction{
+				CallType: "call",
+				From:     "0x1",
+				Gas:      big.NewInt(0),
+				Input:    "0x",
+				To:       "0x2",
+				Value:    big.NewInt(0),
+			},
+		},
+		{
+			name:  "unknown type",
+			trace: `{"type":"unknown","blockNumber":"0x10","action":{"foo":"bar"}}`,
+		},
+		// malformed quantities must be reported instead of panicking
+		{name: "malformed block number", trace: `{"type":"call","blockNumber":"0xzz","action":{}}`, wantErr: true},
+		{name: "malformed gas", trace: `{"type":"call","blockNumber":"0x10","action":{"gas":"gas"}}`, wantErr: true},
+		{name: "malformed gas used", trace: `{"type":"create","blockNumber":"0x10","action":{},"result":{"gasUsed":"0xg"}}`, wantErr: true},
+		{name: "malformed balance", trace: `{"type":"suicide","blockNumber":"0x10","action":{"balance":"-"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trace eth.Trace
+			err := json.Unmarshal([]byte(tt.trace), &trace)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if trace.BlockNumber.Cmp(big.NewInt(16)) != 0 {
+				t.Errorf("block number = %v, want 16", trace.BlockNumber)
+			}
+
+			// big.Int zero values differ in representation, so compare formatted values
+			if fmt.Sprintf("%+v", trace.Action) != fmt.Sprintf("%+v", tt.wantAction) {
+				t.Errorf("action = %#v, want %#v", trace.Action, tt.wantAction)
+			}
+
+			if fmt.Sprintf("%+v", trace.Result) != fmt.Sprintf("%+v", tt.wantResult) {
+				t.Errorf("result = %#v, want %#v", trace.Result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestTraceTransaction(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("trace_transaction", json.RawMessage(`[
+		{"type":"call","blockNumber":"0x10","transactionHash":"0xa","action":{"callType":"call","gas":"0x1","value":"0x0"},"result":{"gasUsed":"0x1","output":"0x"},"subtraces":1,"traceAddress":[]},
+		{"type":"create","blockNumber":"0x10","transactionHash":"0xa","action":{"gas":"0x1","value":"0x0"},"result":{"address":"0x3","gasUsed":"0x1"},"traceAddress":[0]}
+	]`))
+
+	traces, err := eth.NewClient(s.GetblockClient()).Transaction(context.Background(), "0xa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(traces) != 2 {
+		t.Fatalf("got %d traces, want 2", len(traces))
+	}
+
+	if _, ok := traces[1].Action.(*eth.CreateAction); !ok {
+		t.Errorf("action = %T, want *eth.CreateAction", traces[1].Action)
+	}
+
+	if r, ok := traces[1].Result.(*eth.CreateResult); !ok || r.Address != "0x3" {
+		t.Errorf("result = %#v, want create result of 0x3", traces[1].Result)
+	}
+}
+
+func TestTraceTree(t *testing.T) {
+	trace := func(tx string, traceAddress ...int) eth.Trace {
+		return eth.Trace{TransactionHash: tx, TraceAddress: traceAddress}
+	}
+
+	tests := []struct {
+		name   string
+		traces []eth.Trace
+		// want is traces of each root in depth-first order.
+		want []string
+	}{
+		{
+			name:   "empty",
+			traces: nil,
+		},
+		{
+			name: "nested",
+			traces: []eth.Trace{
+				trace("0xa"),
+				trace("0xa", 0),
+				trace("0xa", 0, 0),
+				trace("0xa", 0, 1),
+				trace("0xa", 1),
+			},
+			want: []string{"0xa[] 0xa[0] 0xa[0 0] 0xa[0 1] 0xa[1]"},
+		},
+		{
+			name: "out of order",
+			traces: []eth.Trace{
+				trace("0xa", 1),
+				trace("0xa", 0, 0),
+				trace("0xa"),
+				trace("0xa", 0),
+			},
+			want: []string{"0xa[] 0xa[0] 0xa[0 0] 0xa[1]"},
+		},
+		{
+			name: "multiple transactions",
+			traces: []eth.Trace{
+				trace("0xa"),
+				trace("0xb"),
+				trace("0xb", 0),
+				trace("0xa", 0),
+			},
+			want: []string{"0xa[] 0xa[0]", "0xb[] 0xb[0]"},
+		},
+		{
+			// trace_filter may return nested traces without their parents
+			name: "orphaned",
+			traces: []eth.Trace{
+				trace("0xa", 0, 1),
+				trace("0xa", 0, 1, 0),
+				trace("0xb", 2),
+			},
+			want: []string{"0xa[0 1] 0xa[0 1 0]", "0xb[2]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots := eth.TraceTree(tt.traces)
+
+			var got []string
+			for _, root := range roots {
+				got = append(got, walkTrace(root))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tree = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func walkTrace(n *eth.TraceNode) string {
+	s := fmt.Sprintf("%s%v", n.TransactionHash, n.TraceAddress)
+	for _, c := range n.Calls {
+		s += " " + walkTrace(c)
+	}
+
+	return s
+}
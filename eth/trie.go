@@ -0,0 +1,141 @@
+package eth
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ofen/getblock-go/internal/rlp"
+)
+
+// keccak256 returns Keccak-256 hash of data.
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		h.Write(b)
+	}
+
+	return h.Sum(nil)
+}
+
+// verifyTrieProof walks Merkle Patricia trie with given root hash through proof nodes
+// and returns value stored under key. Nil value is returned if proof proves key absence.
+func verifyTrieProof(root []byte, key []byte, proof [][]byte) ([]byte, error) {
+	nodes := make(map[string][]byte, len(proof))
+	for _, n := range proof {
+		nodes[string(keccak256(n))] = n
+	}
+
+	path := keyNibbles(key)
+	ref := rlp.Item{Data: root}
+	for i := 0; ; i++ {
+		node, err := resolveTrieNode(ref, nodes)
+		if err != nil {
+			return nil, fmt.Errorf("trie node %d: %w", i, err)
+		}
+
+		if node == nil {
+			return nil, nil
+		}
+
+		switch len(node.List) {
+		case 17:
+			if len(path) == 0 {
+				return valueOrNil(node.List[16]), nil
+			}
+
+			ref, path = node.List[path[0]], path[1:]
+		case 2:
+			if node.List[0].IsList {
+				return nil, fmt.Errorf("trie node %d: invalid path", i)
+			}
+
+			nodePath, leaf := compactToNibbles(node.List[0].Data)
+			if leaf {
+				if bytes.Equal(path, nodePath) {
+					return valueOrNil(node.List[1]), nil
+				}
+
+				return nil, nil
+			}
+
+			if !bytes.HasPrefix(path, nodePath) {
+				return nil, nil
+			}
+
+			ref, path = node.List[1], path[len(nodePath):]
+		default:
+			return nil, fmt.Errorf("trie node %d: invalid number of elements: %d", i, len(node.List))
+		}
+	}
+}
+
+// resolveTrieNode returns node referenced by hash or embedded into parent node.
+// Nil node is returned for empty reference.
+func resolveTrieNode(ref rlp.Item, nodes map[string][]byte) (*rlp.Item, error) {
+	if ref.IsList {
+		return &ref, nil
+	}
+
+	if len(ref.Data) == 0 {
+		return nil, nil
+	}
+
+	if len(ref.Data) != 32 {
+		return nil, fmt.Errorf("invalid reference: %x", ref.Data)
+	}
+
+	enc, ok := nodes[string(ref.Data)]
+	if !ok {
+		if bytes.Equal(ref.Data, emptyRootHash) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("missing proof node %#x", ref.Data)
+	}
+
+	node, err := rlp.Decode(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	if !node.IsList {
+		return nil, fmt.Errorf("node %#x is not a list", ref.Data)
+	}
+
+	return &node, nil
+}
+
+func valueOrNil(item rlp.Item) []byte {
+	if item.IsList || len(item.Data) == 0 {
+		return nil
+	}
+
+	return item.Data
+}
+
+func keyNibbles(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2)
+	for i, b := range key {
+		nibbles[i*2] = b >> 4
+		nibbles[i*2+1] = b & 0x0f
+	}
+
+	return nibbles
+}
+
+// compactToNibbles decodes hex prefix encoded path of leaf or extension node.
+func compactToNibbles(compact []byte) (nibbles []byte, leaf bool) {
+	if len(compact) == 0 {
+		return nil, false
+	}
+
+	nibbles = keyNibbles(compact)
+	leaf = nibbles[0]&2 != 0
+	if nibbles[0]&1 != 0 {
+		return nibbles[1:], leaf
+	}
+
+	return nibbles[2:], leaf
+}
@@ -0,0 +1,213 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// BesuPendingTransactions lists pending transactions that match the supplied filter conditions.
+// At most numResults transactions are returned, nil filter matches all transactions.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/txpool_besuPendingTransactions
+func (c *Client) BesuPendingTransactions(ctx context.Context, numResults int, filter *PendingTransactionsFilter) ([]Transaction, error) {
+	params := []interface{}{numResults}
+	if filter != nil {
+		params = append(params, filter)
+	}
+
+	return call[[]Transaction](ctx, c, "txpool_besuPendingTransactions", params...)
+}
+
+// BesuStatistics lists statistics about the node transaction pool.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/txpool_besuStatistics
+func (c *Client) BesuStatistics(ctx context.Context) (*TxPoolStatistics, error) {
+	return call[*TxPoolStatistics](ctx, c, "txpool_besuStatistics")
+}
+
+// BesuTransactions lists transactions in the node transaction pool.
+//
+// https://getblock.io/docs/available-nodes-methods/ETH/JSON-RPC/txpool_besuTransactions
+func (c *Client) BesuTransactions(ctx context.Context) ([]TxPoolTransaction, error) {
+	return call[[]TxPoolTransaction](ctx, c, "txpool_besuTransactions")
+}
+
+// TxPoolContent returns pending and queued transactions of the node transaction pool (Geth).
+func (c *Client) TxPoolContent(ctx context.Context) (*TxPoolContent, error) {
+	return call[*TxPoolContent](ctx, c, "txpool_content")
+}
+
+// TxPoolContentFrom returns pending and queued transactions of the specified sender (Geth).
+func (c *Client) TxPoolContentFrom(ctx context.Context, address string) (*TxPoolAccountContent, error) {
+	return call[*TxPoolAccountContent](ctx, c, "txpool_contentFrom", address)
+}
+
+// TxPoolStatus returns number of pending and queued transactions of the node transaction pool (Geth).
+func (c *Client) TxPoolStatus(ctx context.Context) (*TxPoolStatus, error) {
+	return call[*TxPoolStatus](ctx, c, "txpool_status")
+}
+
+// TxPoolInspect returns textual summary of pending and queued transactions of the node transaction pool (Geth).
+func (c *Client) TxPoolInspect(ctx context.Context) (*TxPoolInspect, error) {
+	return call[*TxPoolInspect](ctx, c, "txpool_inspect")
+}
+
+// TxPool returns snapshot of the node transaction pool regardless of node client.
+// It uses txpool_content and falls back to Besu methods if node does not support it.
+// Besu does not distinguish queued transactions, all of them are reported as pending.
+func (c *Client) TxPool(ctx context.Context) (*TxPoolSnapshot, error) {
+	content, err := c.TxPoolContent(ctx)
+	if err == nil {
+		return &TxPoolSnapshot{
+			Pending: flattenTxPool(content.Pending),
+			Queued:  flattenTxPool(content.Queued),
+		}, nil
+	}
+
+	if !errors.Is(err, ErrUnsupported) {
+		return nil, err
+	}
+
+	stats, err := c.BesuStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &TxPoolSnapshot{}
+	if n := stats.LocalCount + stats.RemoteCount; n > 0 {
+		snapshot.Pending, err = c.BesuPendingTransactions(ctx, int(n), nil)
+		if err != nil {
+			return nil, err
+		}
+		sortTransactions(snapshot.Pending)
+	}
+
+	return snapshot, nil
+}
+
+// TxPoolSnapshot is content of transaction pool normalized across node clients.
+// Transactions are ordered by sender and nonce.
+type TxPoolSnapshot struct {
+	Pending []Transaction
+	Queued  []Transaction
+}
+
+// TxPoolContent is result of txpool_content, transactions keyed by sender and nonce.
+type TxPoolContent struct {
+	Pending map[string]map[string]Transaction `json:"pending"`
+	Queued  map[string]map[string]Transaction `json:"queued"`
+}
+
+// TxPoolAccountContent is result of txpool_contentFrom, transactions keyed by nonce.
+type TxPoolAccountContent struct {
+	Pending map[string]Transaction `json:"pending"`
+	Queued  map[string]Transaction `json:"queued"`
+}
+
+// TxPoolInspect is result of txpool_inspect, transaction summaries keyed by sender and nonce.
+type TxPoolInspect struct {
+	Pending map[string]map[string]string `json:"pending"`
+	Queued  map[string]map[string]string `json:"queued"`
+}
+
+// TxPoolStatus is result of txpool_status.
+type TxPoolStatus struct {
+	Pending *big.Int `json:"pending"`
+	Queued  *big.Int `json:"queued"`
+}
+
+func (s *TxPoolStatus) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Pending quantity `json:"pending"`
+		Queued  quantity `json:"queued"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	s.Pending = aux.Pending.int()
+	s.Queued = aux.Queued.int()
+
+	return nil
+}
+
+// TxPoolStatistics is result of txpool_besuStatistics.
+type TxPoolStatistics struct {
+	MaxSize     int64 `json:"maxSize"`
+	LocalCount  int64 `json:"localCount"`
+	RemoteCount int64 `json:"remoteCount"`
+}
+
+// TxPoolTransaction is result item of txpool_besuTransactions.
+type TxPoolTransaction struct {
+	Hash                      string    `json:"hash"`
+	IsReceivedFromLocalSource bool      `json:"isReceivedFromLocalSource"`
+	AddedToPoolAt             time.Time `json:"addedToPoolAt"`
+}
+
+// PendingTransactionsFilter is filter of txpool_besuPendingTransactions.
+type PendingTransactionsFilter struct {
+	From string
+	To   string
+	// ContractCreation matches only contract creation transactions, To is ignored if set.
+	ContractCreation bool
+	Gas              *FilterCondition
+	GasPrice         *FilterCondition
+	Value            *FilterCondition
+	Nonce            *FilterCondition
+}
+
+func (f PendingTransactionsFilter) MarshalJSON() ([]byte, error) {
+	m := map[string]map[string]string{}
+	if f.From != "" {
+		m["from"] = map[string]string{"eq": f.From}
+	}
+
+	if f.ContractCreation {
+		m["to"] = map[string]string{"action": "contract_creation"}
+	} else if f.To != "" {
+		m["to"] = map[string]string{"eq": f.To}
+	}
+
+	for k, v := range map[string]*FilterCondition{"gas": f.Gas, "gasPrice": f.GasPrice, "value": f.Value, "nonce": f.Nonce} {
+		if v != nil {
+			m[k] = map[string]string{v.Op: int2hex(v.Value)}
+		}
+	}
+
+	return json.Marshal(m)
+}
+
+// FilterCondition compares transaction field with Value. Op is one of "eq", "gt" or "lt".
+type FilterCondition struct {
+	Op    string
+	Value *big.Int
+}
+
+func flattenTxPool(pool map[string]map[string]Transaction) []Transaction {
+	var txs []Transaction
+	for _, byNonce := range pool {
+		for _, tx := range byNonce {
+			txs = append(txs, tx)
+		}
+	}
+
+	sortTransactions(txs)
+
+	return txs
+}
+
+func sortTransactions(txs []Transaction) {
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].From != txs[j].From {
+			return txs[i].From < txs[j].From
+		}
+
+		return txs[i].Nonce.Cmp(txs[j].Nonce) < 0
+	})
+}
@@ -0,0 +1,214 @@
+package eth_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ofen/getblock-go/eth"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestTxPool(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(s *getblocktest.Server)
+		// want is sender:nonce of pending and queued transactions.
+		wantPending []string
+		wantQueued  []string
+		// wantCalls is methods called in order.
+		wantCalls []string
+	}{
+		{
+			name: "geth",
+			setup: func(s *getblocktest.Server) {
+				s.Respond("txpool_content", json.RawMessage(`{
+					"pending": {
+						"0xb": {"1": {"from": "0xb", "nonce": "0x1"}, "0": {"from": "0xb", "nonce": "0x0"}},
+						"0xa": {"10": {"from": "0xa", "nonce": "0xa"}, "2": {"from": "0xa", "nonce": "0x2"}}
+					},
+					"queued": {"0xa": {"12": {"from": "0xa", "nonce": "0xc"}}}
+				}`))
+			},
+			wantPending: []string{"0xa:2", "0xa:10", "0xb:0", "0xb:1"},
+			wantQueued:  []string{"0xa:12"},
+			wantCalls:   []string{"txpool_content"},
+		},
+		{
+			name: "besu",
+			setup: func(s *getblocktest.Server) {
+				s.Respond("txpool_besuStatistics", map[string]int{"maxSize": 4096, "localCount": 1, "remoteCount": 2})
+				s.Respond("txpool_besuPendingTransactions", json.RawMessage(`[
+					{"from": "0xb", "nonce": "0x0"},
+					{"from": "0xa", "nonce": "0x3"},
+					{"from": "0xa", "nonce": "0x1"}
+				]`))
+			},
+			wantPending: []string{"0xa:1", "0xa:3", "0xb:0"},
+			wantCalls:   []string{"txpool_content", "txpool_besuStatistics", "txpool_besuPendingTransactions"},
+		},
+		{
+			name: "besu empty pool",
+			setup: func(s *getblocktest.Server) {
+				s.Respond("txpool_besuStatistics", map[string]int{"maxSize": 4096})
+			},
+			wantCalls: []string{"txpool_content", "txpool_besuStatistics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			tt.setup(s)
+
+			snapshot, err := eth.NewClient(s.GetblockClient()).TxPool(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := txKeys(snapshot.Pending); !equalStrings(got, tt.wantPending) {
+				t.Errorf("pending = %v, want %v", got, tt.wantPending)
+			}
+
+			if got := txKeys(snapshot.Queued); !equalStrings(got, tt.wantQueued) {
+				t.Errorf("queued = %v, want %v", got, tt.wantQueued)
+			}
+
+			var methods []string
+			for _, c := range s.Calls() {
+				methods = append(methods, c.Method)
+			}
+			if !equalStrings(methods, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", methods, tt.wantCalls)
+			}
+
+			// number of pending transactions is requested as reported by statistics
+			if calls := s.Calls("txpool_besuPendingTransactions"); len(calls) == 1 {
+				var n int
+				if err := calls[0].Param(0, &n); err != nil || n != 3 {
+					t.Errorf("numResults = %d, want 3", n)
+				}
+			}
+		})
+	}
+}
+
+func TestTxPoolError(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.RespondError("txpool_content", getblocktest.CodeInternalError, "boom")
+
+	if _, err := eth.NewClient(s.GetblockClient()).TxPool(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// only unsupported txpool_content falls back to Besu methods
+	if calls := s.Calls("txpool_besuStatistics"); len(calls) != 0 {
+		t.Errorf("got %d txpool_besuStatistics calls, want 0", len(calls))
+	}
+}
+
+func TestTxPoolStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		wantPending int64
+		wantQueued  int64
+		wantErr     bool
+	}{
+		{name: "valid", status: `{"pending": "0x10", "queued": "0x7"}`, wantPending: 16, wantQueued: 7},
+		// malformed quantities must be reported instead of panicking
+		{name: "malformed pending", status: `{"pending": "0xzz", "queued": "0x7"}`, wantErr: true},
+		{name: "malformed queued", status: `{"pending": "0x10", "queued": "many"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			s.Respond("txpool_status", json.RawMessage(tt.status))
+
+			status, err := eth.NewClient(s.GetblockClient()).TxPoolStatus(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if status.Pending.Int64() != tt.wantPending || status.Queued.Int64() != tt.wantQueued {
+				t.Errorf("status = %v/%v, want %d/%d", status.Pending, status.Queued, tt.wantPending, tt.wantQueued)
+			}
+		})
+	}
+}
+
+func TestPendingTransactionsFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter eth.PendingTransactionsFilter
+		want   string
+	}{
+		{name: "empty", want: `{}`},
+		{
+			name:   "from and to",
+			filter: eth.PendingTransactionsFilter{From: "0xa", To: "0xb"},
+			want:   `{"from":{"eq":"0xa"},"to":{"eq":"0xb"}}`,
+		},
+		{
+			name:   "contract creation",
+			filter: eth.PendingTransactionsFilter{To: "0xb", ContractCreation: true},
+			want:   `{"to":{"action":"contract_creation"}}`,
+		},
+		{
+			name: "conditions",
+			filter: eth.PendingTransactionsFilter{
+				Gas:   &eth.FilterCondition{Op: "lt", Value: big.NewInt(eth.GWei)},
+				Nonce: &eth.FilterCondition{Op: "eq", Value: big.NewInt(eth.Wei)},
+			},
+			want: `{"gas":{"lt":"0x3b9aca00"},"nonce":{"eq":"0x1"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("filter = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func txKeys(txs []eth.Transaction) []string {
+	var keys []string
+	for _, tx := range txs {
+		keys = append(keys, tx.From+":"+tx.Nonce.String())
+	}
+
+	return keys
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
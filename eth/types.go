@@ -2,11 +2,12 @@ package eth
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"time"
 )
 
-// Block is block representations
+// Block is block representations. Transactions contain only Hash if block is requested without detailed transactions.
 type Block struct {
 	BaseFeePerGas    *big.Int      `json:"baseFeePerGas"`
 	Difficulty       *big.Int      `json:"difficulty"`
@@ -35,14 +36,14 @@ func (t *Block) UnmarshalJSON(data []byte) error {
 	type alias Block
 
 	aux := &struct {
-		BaseFeePerGas   string `json:"baseFeePerGas"`
-		Difficulty      string `json:"difficulty"`
-		GasLimit        string `json:"gasLimit"`
-		GasUsed         string `json:"gasUsed"`
-		Number          string `json:"number"`
-		Size            string `json:"size"`
-		Timestamp       string `json:"timestamp"`
-		TotalDifficulty string `json:"totalDifficulty"`
+		BaseFeePerGas   quantity `json:"baseFeePerGas"`
+		Difficulty      quantity `json:"difficulty"`
+		GasLimit        quantity `json:"gasLimit"`
+		GasUsed         quantity `json:"gasUsed"`
+		Number          quantity `json:"number"`
+		Size            quantity `json:"size"`
+		Timestamp       quantity `json:"timestamp"`
+		TotalDifficulty quantity `json:"totalDifficulty"`
 		*alias
 	}{
 		alias: (*alias)(t),
@@ -52,14 +53,14 @@ func (t *Block) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	t.BaseFeePerGas = hex2int(aux.BaseFeePerGas)
-	t.Difficulty = hex2int(aux.Difficulty)
-	t.GasLimit = hex2int(aux.GasLimit)
-	t.GasUsed = hex2int(aux.GasUsed)
-	t.Number = hex2int(aux.Number)
-	t.Size = hex2int(aux.Size)
-	t.Timestamp = time.Unix(hex2int(aux.Timestamp).Int64(), 0)
-	t.TotalDifficulty = hex2int(aux.TotalDifficulty)
+	t.BaseFeePerGas = aux.BaseFeePerGas.int()
+	t.Difficulty = aux.Difficulty.int()
+	t.GasLimit = aux.GasLimit.int()
+	t.GasUsed = aux.GasUsed.int()
+	t.Number = aux.Number.int()
+	t.Size = aux.Size.int()
+	t.Timestamp = time.Unix(aux.Timestamp.int().Int64(), 0)
+	t.TotalDifficulty = aux.TotalDifficulty.int()
 
 	return nil
 }
@@ -90,18 +91,23 @@ type Transaction struct {
 func (t *Transaction) UnmarshalJSON(data []byte) error {
 	type alias Transaction
 
+	// Blocks requested without detailed transactions contain only hashes.
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &t.Hash)
+	}
+
 	aux := &struct {
-		BlockNumber          string `json:"blockNumber"`
-		Gas                  string `json:"gas"`
-		GasPrice             string `json:"gasPrice"`
-		Nonce                string `json:"nonce"`
-		TransactionIndex     string `json:"transactionIndex"`
-		Value                string `json:"value"`
-		Type                 string `json:"type"`
-		MaxFeePerGas         string `json:"maxFeePerGas"`
-		MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
-		V                    string `json:"v"`
-		ChainID              string `json:"chainId"`
+		BlockNumber          quantity `json:"blockNumber"`
+		Gas                  quantity `json:"gas"`
+		GasPrice             quantity `json:"gasPrice"`
+		Nonce                quantity `json:"nonce"`
+		TransactionIndex     quantity `json:"transactionIndex"`
+		Value                quantity `json:"value"`
+		Type                 quantity `json:"type"`
+		MaxFeePerGas         quantity `json:"maxFeePerGas"`
+		MaxPriorityFeePerGas quantity `json:"maxPriorityFeePerGas"`
+		V                    quantity `json:"v"`
+		ChainID              quantity `json:"chainId"`
 		*alias
 	}{
 		alias: (*alias)(t),
@@ -111,17 +117,170 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	t.BlockNumber = hex2int(aux.BlockNumber)
-	t.Gas = hex2int(aux.Gas)
-	t.GasPrice = hex2int(aux.GasPrice)
-	t.Nonce = hex2int(aux.Nonce)
-	t.TransactionIndex = hex2int(aux.TransactionIndex)
-	t.Value = hex2int(aux.Value)
-	t.Type = hex2int(aux.Type)
-	t.MaxFeePerGas = hex2int(aux.MaxFeePerGas)
-	t.MaxPriorityFeePerGas = hex2int(aux.MaxPriorityFeePerGas)
-	t.V = hex2int(aux.V)
-	t.ChainID = hex2int(aux.ChainID)
+	t.BlockNumber = aux.BlockNumber.int()
+	t.Gas = aux.Gas.int()
+	t.GasPrice = aux.GasPrice.int()
+	t.Nonce = aux.Nonce.int()
+	t.TransactionIndex = aux.TransactionIndex.int()
+	t.Value = aux.Value.int()
+	t.Type = aux.Type.int()
+	t.MaxFeePerGas = aux.MaxFeePerGas.int()
+	t.MaxPriorityFeePerGas = aux.MaxPriorityFeePerGas.int()
+	t.V = aux.V.int()
+	t.ChainID = aux.ChainID.int()
+
+	return nil
+}
+
+// Receipt is transaction receipt representation.
+type Receipt struct {
+	BlockHash         string   `json:"blockHash"`
+	BlockNumber       *big.Int `json:"blockNumber"`
+	ContractAddress   string   `json:"contractAddress"`
+	CumulativeGasUsed *big.Int `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *big.Int `json:"effectiveGasPrice"`
+	From              string   `json:"from"`
+	GasUsed           *big.Int `json:"gasUsed"`
+	Logs              []Log    `json:"logs"`
+	LogsBloom         string   `json:"logsBloom"`
+	Status            *big.Int `json:"status"`
+	To                string   `json:"to"`
+	TransactionHash   string   `json:"transactionHash"`
+	TransactionIndex  *big.Int `json:"transactionIndex"`
+	Type              *big.Int `json:"type"`
+}
+
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	type alias Receipt
+
+	aux := &struct {
+		BlockNumber       quantity `json:"blockNumber"`
+		CumulativeGasUsed quantity `json:"cumulativeGasUsed"`
+		EffectiveGasPrice quantity `json:"effectiveGasPrice"`
+		GasUsed           quantity `json:"gasUsed"`
+		Status            quantity `json:"status"`
+		TransactionIndex  quantity `json:"transactionIndex"`
+		Type              quantity `json:"type"`
+		*alias
+	}{
+		alias: (*alias)(r),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.BlockNumber = aux.BlockNumber.int()
+	r.CumulativeGasUsed = aux.CumulativeGasUsed.int()
+	r.EffectiveGasPrice = aux.EffectiveGasPrice.int()
+	r.GasUsed = aux.GasUsed.int()
+	r.Status = aux.Status.int()
+	r.TransactionIndex = aux.TransactionIndex.int()
+	r.Type = aux.Type.int()
+
+	return nil
+}
+
+// Log is log emitted by contract. Removed is true if log was removed due to chain reorganization.
+type Log struct {
+	Address          string   `json:"address"`
+	BlockHash        string   `json:"blockHash"`
+	BlockNumber      *big.Int `json:"blockNumber"`
+	Data             string   `json:"data"`
+	LogIndex         *big.Int `json:"logIndex"`
+	Removed          bool     `json:"removed"`
+	Topics           []string `json:"topics"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex *big.Int `json:"transactionIndex"`
+}
+
+func (l *Log) UnmarshalJSON(data []byte) error {
+	type alias Log
+
+	aux := &struct {
+		BlockNumber      quantity `json:"blockNumber"`
+		LogIndex         quantity `json:"logIndex"`
+		TransactionIndex quantity `json:"transactionIndex"`
+		*alias
+	}{
+		alias: (*alias)(l),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	l.BlockNumber = aux.BlockNumber.int()
+	l.LogIndex = aux.LogIndex.int()
+	l.TransactionIndex = aux.TransactionIndex.int()
+
+	return nil
+}
+
+// CallMsg is transaction call object used by eth_call, eth_estimateGas and tracing methods.
+type CallMsg struct {
+	From     string
+	To       string
+	Gas      *big.Int
+	GasPrice *big.Int
+	Value    *big.Int
+	Data     string
+}
+
+func (m CallMsg) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		From     string `json:"from,omitempty"`
+		To       string `json:"to,omitempty"`
+		Gas      string `json:"gas,omitempty"`
+		GasPrice string `json:"gasPrice,omitempty"`
+		Value    string `json:"value,omitempty"`
+		Data     string `json:"data,omitempty"`
+	}{
+		From:     m.From,
+		To:       m.To,
+		Gas:      optionalHex(m.Gas),
+		GasPrice: optionalHex(m.GasPrice),
+		Value:    optionalHex(m.Value),
+		Data:     m.Data,
+	}
+
+	return json.Marshal(aux)
+}
+
+// optionalHex returns hex representation of i or empty string if i is nil.
+func optionalHex(i *big.Int) string {
+	if i == nil {
+		return ""
+	}
+
+	return int2hex(i)
+}
+
+// quantity is hex or decimal integer in JSON string or number. Unlike hex2int
+// it fails to decode malformed value instead of panicking.
+type quantity string
+
+func (q *quantity) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	} else if s == "null" {
+		s = ""
+	}
+
+	if s != "" {
+		if _, ok := new(big.Int).SetString(s, 0); !ok {
+			return fmt.Errorf("invalid quantity %q", s)
+		}
+	}
+
+	*q = quantity(s)
 
 	return nil
 }
+
+func (q quantity) int() *big.Int {
+	return hex2int(string(q))
+}
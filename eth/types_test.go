@@ -0,0 +1,118 @@
+package eth_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/eth"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func BenchmarkCallForBlock(b *testing.B) {
+	for _, txs := range []int{100, 500} {
+		b.Run(fmt.Sprintf("txs=%d", txs), func(b *testing.B) {
+			path := recordBlock(b, txs)
+
+			cassette, err := getblock.NewCassette(path, getblock.Replay, nil, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			c := getblock.New("", "http://127.0.0.1:0/", getblock.WithHTTPClient(&http.Client{Transport: cassette}))
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				block, err := getblock.CallFor[*eth.Block](ctx, c, "eth_getBlockByNumber", "0x1", true)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(block.Transactions) != txs {
+					b.Fatalf("got %d transactions, want %d", len(block.Transactions), txs)
+				}
+			}
+		})
+	}
+}
+
+// recordBlock records block with txs transactions to cassette and returns its path.
+func recordBlock(b *testing.B, txs int) string {
+	b.Helper()
+
+	const alice = "0x00000000000000000000000000000000000a11ce"
+	backend := getblocktest.NewBackend(map[string]*big.Int{alice: big.NewInt(eth.Ether)})
+	defer backend.Close()
+
+	for i := 0; i < txs; i++ {
+		to := fmt.Sprintf("0x%040x", i+1)
+		if _, err := backend.Transfer(alice, to, big.NewInt(int64(i+1))); err != nil {
+			b.Fatal(err)
+		}
+	}
+	backend.Commit()
+
+	path := filepath.Join(b.TempDir(), "block.json")
+	cassette, err := getblock.NewCassette(path, getblock.Record, nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	c := backend.GetblockClient(getblock.WithHTTPClient(&http.Client{Transport: cassette}))
+	if _, err := c.Call(context.Background(), "eth_getBlockByNumber", "0x1", true); err != nil {
+		b.Fatal(err)
+	}
+
+	return path
+}
+
+func TestTypesUnmarshal(t *testing.T) {
+	var block eth.Block
+	data := `{"number": "0x10", "timestamp": "0x5f5e100", "baseFeePerGas": null, "transactions": ["0xaa",
+		{"hash": "0xbb", "nonce": "0x1", "value": "1000", "chainId": "0x1", "blockNumber": null}]}`
+	if err := json.Unmarshal([]byte(data), &block); err != nil {
+		t.Fatal(err)
+	}
+
+	if block.Number.Int64() != 16 || block.Timestamp.Unix() != 100000000 || block.BaseFeePerGas.Sign() != 0 {
+		t.Errorf("block = %+v", block)
+	}
+
+	if len(block.Transactions) != 2 || block.Transactions[0].Hash != "0xaa" {
+		t.Fatalf("transactions = %+v", block.Transactions)
+	}
+
+	tx := block.Transactions[1]
+	if tx.Hash != "0xbb" || tx.Nonce.Int64() != 1 || tx.Value.Int64() != 1000 || tx.ChainID.Int64() != 1 || tx.BlockNumber.Sign() != 0 {
+		t.Errorf("transaction = %+v", tx)
+	}
+
+	tests := []struct {
+		name string
+		v    interface{}
+		data string
+	}{
+		{name: "block number", v: &eth.Block{}, data: `{"number": "0xzz"}`},
+		{name: "block timestamp", v: &eth.Block{}, data: `{"timestamp": "yesterday"}`},
+		{name: "nested transaction", v: &eth.Block{}, data: `{"transactions": [{"gas": "-"}]}`},
+		{name: "transaction value", v: &eth.Transaction{}, data: `{"value": "0x1.5"}`},
+		{name: "transaction v", v: &eth.Transaction{}, data: `{"v": true}`},
+		{name: "receipt status", v: &eth.Receipt{}, data: `{"status": "ok"}`},
+		{name: "receipt log", v: &eth.Receipt{}, data: `{"logs": [{"logIndex": "0xg"}]}`},
+		{name: "log block number", v: &eth.Log{}, data: `{"blockNumber": "latest"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(tt.data), tt.v); err == nil {
+				t.Errorf("expected error, got %+v", tt.v)
+			}
+		})
+	}
+}
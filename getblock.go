@@ -2,45 +2,411 @@ package getblock
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/ybbus/jsonrpc/v3"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const authorizationHeaderKey = "x-api-key"
 
+// defaultAttempts is default maximum number of attempts per call.
+const defaultAttempts = 5
+
+// ErrNoEndpoints is returned by Call of Client without endpoints.
+var ErrNoEndpoints = errors.New("getblock: no endpoints")
+
+// ErrInvalidResponse is returned when response of endpoint is not valid JSON-RPC response.
+// Such calls are not retried.
+var ErrInvalidResponse = errors.New("getblock: invalid response")
+
 // New creates Client.
-func New(token string, endpoint string) *Client {
-	opts := &jsonrpc.RPCClientOpts{}
-	if token != "" {
-		opts.CustomHeaders = map[string]string{authorizationHeaderKey: token}
+func New(token string, endpoint string, opts ...Option) *Client {
+	return NewPool([]Endpoint{{URL: endpoint, Token: token}}, opts...)
+}
+
+// NewPool creates Client balancing requests across several endpoints.
+// Close must be called to release resources if health checks are enabled.
+func NewPool(endpoints []Endpoint, opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		attempts:   defaultAttempts,
+		usage:      newUsageTracker(),
+		done:       make(chan struct{}),
+
+		logLevel:       slog.LevelDebug,
+		logFailedLevel: slog.LevelWarn,
+		logBodyLimit:   defaultLogBodyLimit,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.redactor = newRedactor(endpoints)
+
+	httpClient := c.httpClient
+	if c.logger != nil {
+		httpClient = c.withLogTransport(httpClient)
+	}
+
+	httpClient = withHeaderTransport(httpClient)
+	for _, e := range endpoints {
+		ep := newEndpoint(e, httpClient)
+		if c.breaker != nil {
+			ep.circuit = &circuit{cfg: c.breaker, host: ep.host}
+		}
+
+		c.endpoints = append(c.endpoints, ep)
+	}
+
+	c.handler = c.chain()
+
+	if c.healthCheck.interval > 0 {
+		go c.runHealthChecks()
 	}
 
-	return &Client{jsonrpc.NewClientWithOpts(endpoint, opts)}
+	return c
+}
+
+// Caller sends JSON-RPC calls. It is implemented by Client and Quorum.
+type Caller interface {
+	Call(ctx context.Context, method string, params ...interface{}) (*Response, error)
+}
+
+// CallFor sends call with c and decodes result into T. JSON-RPC error is returned as *RPCError,
+// null result gives zero T.
+func CallFor[T any](ctx context.Context, c Caller, method string, params ...interface{}) (T, error) {
+	var v T
+	r, err := c.Call(ctx, method, params...)
+	if err != nil {
+		return v, err
+	}
+
+	if r.Error != nil {
+		return v, r.Error
+	}
+
+	if isNull(r.Result) {
+		return v, nil
+	}
+
+	if err := json.Unmarshal(r.Result, &v); err != nil {
+		var zero T
+		return zero, fmt.Errorf("getblock: %s: decode result: %w", method, err)
+	}
+
+	return v, nil
+}
+
+// Option configures Client.
+type Option func(*Client)
+
+// WithHTTPClient sets HTTP client used for requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
 }
 
 // Client is common JSON-RPC client.
 type Client struct {
-	Client jsonrpc.RPCClient
-}
-
-// Call sends request to JSON-RPC endpoint.
-// Repeats request on 5xx error up to 5 times.
-func (c *Client) Call(ctx context.Context, method string, params ...interface{}) (*jsonrpc.RPCResponse, error) {
-	var r *jsonrpc.RPCResponse
-	var err error
-	for i := 0; i < 5; i++ {
-		r, err = c.Client.Call(ctx, method, params)
-		if err == nil {
-			break
+	// accessed atomically, first for alignment
+	next        uint64
+	ids         uint64
+	cacheHits   int64
+	cacheMisses int64
+
+	endpoints          []*endpoint
+	policy             Policy
+	httpClient         *http.Client
+	healthCheck        healthCheck
+	attempts           int
+	limiter            *rateLimiter
+	weights            map[string]int
+	usage              *usageTracker
+	cache              Cache
+	flights            *flightGroup
+	hedger             *hedger
+	breaker            *CircuitBreaker
+	middlewares        []Middleware
+	attemptMiddlewares []Middleware
+	handler            Handler
+	tracer             trace.Tracer
+	metrics            *Metrics
+	logger             *slog.Logger
+	logLevel           slog.Level
+	logFailedLevel     slog.Level
+	logBodyLimit       int
+	redactor           *strings.Replacer
+	done               chan struct{}
+	closeOnce          sync.Once
+
+	finalizedMu sync.Mutex
+	finalized   uint64
+	finalizedAt time.Time
+}
+
+// Call sends request to JSON-RPC endpoint through middleware chain.
+// Repeats request on transport or 5xx error up to 5 times failing over to next endpoint.
+// Per-call options are set with WithNoRetry, WithTimeout, WithEndpoint, WithHeader and WithCacheBypass.
+func (c *Client) Call(ctx context.Context, method string, params ...interface{}) (*Response, error) {
+	ctx, cancel, header := callOptionsFromContext(ctx).apply(ctx)
+	defer cancel()
+
+	return c.handler.Handle(contextWithCallInfo(ctx), &Request{Method: method, Params: params, Header: header})
+}
+
+// Close stops background health checks.
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// chain builds handler of Client. From outermost to innermost: tracing, metrics, middlewares set with WithMiddleware,
+// cache, deduplication, retry, hedging, rate limiting, middlewares set with WithAttemptMiddleware.
+// Usage is accounted by send once endpoint is chosen.
+func (c *Client) chain() Handler {
+	h := Chain(HandlerFunc(c.send), c.attemptMiddlewares...)
+	if c.limiter != nil {
+		h = c.limiter.middleware(c.weights)(h)
+	}
+
+	if c.hedger != nil && len(c.endpoints) > 1 {
+		h = c.hedger.middleware(h)
+	}
+
+	h = Retry(c.attempts)(h)
+	if c.flights != nil {
+		h = c.flights.middleware(h)
+	}
+
+	if c.cache != nil {
+		h = c.cacheMiddleware(h)
+	}
+
+	h = Chain(h, c.middlewares...)
+	if c.metrics != nil {
+		h = c.metrics.middleware(h)
+	}
+
+	if c.tracer != nil {
+		h = c.tracingMiddleware(h)
+	}
+
+	return h
+}
+
+// send sends request to endpoint chosen according to policy and health.
+func (c *Client) send(ctx context.Context, req *Request) (*Response, error) {
+	data, err := json.Marshal(newRequest(atomic.AddUint64(&c.ids, 1), req.Method, req.Params))
+	if err != nil {
+		return nil, fmt.Errorf("getblock: %s: encode request: %w", req.Method, err)
+	}
+
+	var r *Response
+	err = c.roundTrip(ctx, req.Header, []string{req.Method}, func(ctx context.Context, e *endpoint) error {
+		if err := e.call(ctx, data, &r); err != nil {
+			return err
 		}
 
-		e, ok := err.(*jsonrpc.HTTPError)
-		if ok {
-			if e.Code < 500 {
-				break
-			}
+		if r == nil {
+			return fmt.Errorf("%w: %s: empty response", ErrInvalidResponse, req.Method)
 		}
-	}
+
+		return nil
+	})
 
 	return r, err
 }
+
+// roundTrip charges methods and calls call with endpoint chosen according to policy and health,
+// then records outcome in endpoint health, circuit breaker and call info.
+func (c *Client) roundTrip(ctx context.Context, header http.Header, methods []string, call func(context.Context, *endpoint) error) error {
+	e, err := c.pick(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.usage.charge(methods...); err != nil {
+		if e.circuit != nil {
+			// release half-open probe acquired by pick
+			e.circuit.record(err, true)
+		}
+
+		return err
+	}
+
+	start := time.Now()
+	err = call(contextWithHeader(ctx, header), e)
+	if ctx.Err() == nil {
+		e.observe(time.Since(start), err)
+	}
+
+	if e.circuit != nil {
+		e.circuit.record(err, ctx.Err() != nil)
+	}
+
+	if info := callInfoFromContext(ctx); info != nil {
+		info.record(e.host, httpStatus(err), isHedge(ctx))
+	}
+
+	return err
+}
+
+// isRetryable reports whether request failed on transport level or with 5xx error.
+// JSON-RPC errors and invalid responses are not retried.
+func isRetryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code >= 500
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+
+	var quotaErr *QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		return false
+	}
+
+	var circuitErr *CircuitOpenError
+	if errors.As(err, &circuitErr) {
+		return false
+	}
+
+	return !errors.Is(err, ErrNoEndpoints) && !errors.Is(err, ErrInvalidResponse) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// httpStatus returns HTTP status code of response, 0 if request failed before response was received.
+func httpStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return 0
+}
+
+type callInfoContextKey struct{}
+
+// callInfo is outcome of call attempts collected for instrumentation.
+type callInfo struct {
+	mu       sync.Mutex
+	attempts int
+	// hedges is number of duplicate requests sent by hedging, they are not counted in attempts.
+	hedges int
+	host   string
+	status int
+}
+
+func contextWithCallInfo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, callInfoContextKey{}, &callInfo{})
+}
+
+func callInfoFromContext(ctx context.Context) *callInfo {
+	info, _ := ctx.Value(callInfoContextKey{}).(*callInfo)
+	return info
+}
+
+// record accounts attempt or hedged request sent to host which ended with HTTP status.
+func (i *callInfo) record(host string, status int, hedge bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if hedge {
+		i.hedges++
+	} else {
+		i.attempts++
+	}
+	i.host = host
+	i.status = status
+}
+
+// add accounts attempts of other call, e.g. shared by deduplication.
+func (i *callInfo) add(other *callInfo) {
+	s := other.snapshot()
+	if s.attempts == 0 {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.attempts += s.attempts
+	i.hedges += s.hedges
+	i.host = s.host
+	i.status = s.status
+}
+
+// callSnapshot is outcome of call attempts.
+type callSnapshot struct {
+	attempts int
+	hedges   int
+	// host and status are host and HTTP status of the last request.
+	host   string
+	status int
+}
+
+// snapshot returns outcome of call attempts.
+func (i *callInfo) snapshot() callSnapshot {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return callSnapshot{attempts: i.attempts, hedges: i.hedges, host: i.host, status: i.status}
+}
+
+type headerContextKey struct{}
+
+// contextWithHeader attaches HTTP headers to be sent with request.
+func contextWithHeader(ctx context.Context, h http.Header) context.Context {
+	if len(h) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, headerContextKey{}, h)
+}
+
+// headerTransport sets HTTP headers attached to request context.
+type headerTransport struct {
+	base http.RoundTripper
+}
+
+func withHeaderTransport(c *http.Client) *http.Client {
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	hc := *c
+	hc.Transport = &headerTransport{base: base}
+
+	return &hc
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	h, ok := req.Context().Value(headerContextKey{}).(http.Header)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	for k, v := range h {
+		req.Header[k] = v
+	}
+
+	return t.base.RoundTrip(req)
+}
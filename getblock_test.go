@@ -0,0 +1,118 @@
+package getblock_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+type callForBlock struct {
+	Number string   `json:"number"`
+	Hash   string   `json:"hash"`
+	Txs    []string `json:"transactions"`
+}
+
+func TestCallFor(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_blockNumber", "0x10")
+	s.Respond("eth_getBlockByNumber", json.RawMessage(`{"number":"0x10","hash":"0xab","transactions":["0x1","0x2"]}`))
+	s.Respond("eth_accounts", []string{"0xa", "0xb"})
+	s.Respond("eth_getTransactionByHash", nil)
+	s.Respond("eth_syncing", false)
+	s.RespondError("eth_call", getblocktest.CodeServerError, "execution reverted")
+
+	c := s.GetblockClient(getblock.WithRetry(1))
+	ctx := context.Background()
+
+	t.Run("string", func(t *testing.T) {
+		v, err := getblock.CallFor[string](ctx, c, "eth_blockNumber")
+		if err != nil || v != "0x10" {
+			t.Errorf("result = %q, error %v, want 0x10", v, err)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		v, err := getblock.CallFor[callForBlock](ctx, c, "eth_getBlockByNumber", "0x10", false)
+		want := callForBlock{Number: "0x10", Hash: "0xab", Txs: []string{"0x1", "0x2"}}
+		if err != nil || !reflect.DeepEqual(v, want) {
+			t.Errorf("result = %+v, error %v, want %+v", v, err, want)
+		}
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		v, err := getblock.CallFor[*callForBlock](ctx, c, "eth_getBlockByNumber", "0x10", false)
+		if err != nil || v == nil || v.Hash != "0xab" {
+			t.Errorf("result = %+v, error %v, want block 0xab", v, err)
+		}
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		v, err := getblock.CallFor[[]string](ctx, c, "eth_accounts")
+		if err != nil || !reflect.DeepEqual(v, []string{"0xa", "0xb"}) {
+			t.Errorf("result = %v, error %v, want [0xa 0xb]", v, err)
+		}
+	})
+
+	t.Run("bool", func(t *testing.T) {
+		v, err := getblock.CallFor[bool](ctx, c, "eth_syncing")
+		if err != nil || v {
+			t.Errorf("result = %v, error %v, want false", v, err)
+		}
+	})
+
+	t.Run("null result", func(t *testing.T) {
+		if v, err := getblock.CallFor[*callForBlock](ctx, c, "eth_getTransactionByHash", "0x1"); err != nil || v != nil {
+			t.Errorf("pointer result = %+v, error %v, want nil", v, err)
+		}
+
+		if v, err := getblock.CallFor[callForBlock](ctx, c, "eth_getTransactionByHash", "0x1"); err != nil || !reflect.DeepEqual(v, callForBlock{}) {
+			t.Errorf("struct result = %+v, error %v, want zero", v, err)
+		}
+
+		if v, err := getblock.CallFor[[]string](ctx, c, "eth_getTransactionByHash", "0x1"); err != nil || v != nil {
+			t.Errorf("slice result = %v, error %v, want nil", v, err)
+		}
+	})
+
+	t.Run("rpc error", func(t *testing.T) {
+		v, err := getblock.CallFor[string](ctx, c, "eth_call")
+
+		var rpcErr *getblock.RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != getblocktest.CodeServerError || rpcErr.Message != "execution reverted" {
+			t.Errorf("error = %v, want execution reverted", err)
+		}
+
+		if v != "" {
+			t.Errorf("result = %q, want empty", v)
+		}
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		v, err := getblock.CallFor[[]string](ctx, c, "eth_getBlockByNumber", "0x10", false)
+
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Errorf("error = %v, want wrapped json.UnmarshalTypeError", err)
+		}
+
+		if v != nil {
+			t.Errorf("result = %v, want nil", v)
+		}
+	})
+
+	t.Run("call error", func(t *testing.T) {
+		s.Inject(getblocktest.Fault{Times: 1, Status: http.StatusServiceUnavailable})
+
+		var httpErr *getblock.HTTPError
+		if _, err := getblock.CallFor[string](ctx, c, "eth_blockNumber"); !errors.As(err, &httpErr) || httpErr.Code != http.StatusServiceUnavailable {
+			t.Errorf("error = %v, want HTTP 503", err)
+		}
+	})
+}
@@ -0,0 +1,965 @@
+package getblocktest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ofen/getblock-go/internal/rlp"
+)
+
+const (
+	// ChainID is chain ID of Backend.
+	ChainID = 1337
+
+	// transferGas is gas used by value transfer.
+	transferGas = 21000
+	// blockTime is seconds between timestamps of consecutive blocks.
+	blockTime = 12
+	gasLimit  = 30000000
+)
+
+var (
+	// DefaultGasPrice is gas price of transactions which do not set it.
+	DefaultGasPrice = big.NewInt(1e9)
+
+	zeroAddress = "0x" + strings.Repeat("0", 40)
+	zeroHash    = "0x" + strings.Repeat("0", 64)
+	zeroBloom   = "0x" + strings.Repeat("0", 512)
+	// emptyUnclesHash is hash of empty list of uncles.
+	emptyUnclesHash = "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"
+
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+// Backend is simulated in-memory Ethereum chain served over JSON-RPC by embedded Server.
+// Accounts are unlocked: value transfers are sent with eth_sendTransaction or Transfer and
+// included in block by Commit. Signed legacy value transfers are accepted by eth_sendRawTransaction.
+// Accounts have no code and storage, so eth_call returns empty output. Logs are scripted with AddLog.
+// Tags "safe" and "finalized" refer to latest block.
+//
+// Supported methods: rpc_modules, web3_clientVersion, net_version, eth_chainId, eth_syncing, eth_accounts,
+// eth_blockNumber, eth_gasPrice, eth_maxPriorityFeePerGas, eth_estimateGas, eth_getBalance,
+// eth_getTransactionCount, eth_getCode, eth_getStorageAt, eth_call, eth_getBlockByNumber, eth_getBlockByHash,
+// eth_getBlockTransactionCountByNumber, eth_getBlockTransactionCountByHash, eth_sendTransaction,
+// eth_sendRawTransaction, eth_getTransactionByHash, eth_getTransactionReceipt, eth_getLogs, eth_newFilter,
+// eth_newBlockFilter, eth_newPendingTransactionFilter, eth_getFilterChanges, eth_getFilterLogs and eth_uninstallFilter.
+type Backend struct {
+	*Server
+
+	mu       sync.Mutex
+	genesis  time.Time
+	blocks   []*simBlock
+	pending  []*simTx
+	state    simState
+	txs      map[string]*simTx
+	filters  map[string]*filter
+	filterID uint64
+	reorgs   int
+}
+
+type simState map[string]*simAccount
+
+type simAccount struct {
+	balance *big.Int
+	nonce   uint64
+}
+
+type simBlock struct {
+	number uint64
+	hash   string
+	parent string
+	txs    []*simTx
+	state  simState
+}
+
+type simTx struct {
+	hash     string
+	from     string
+	to       string
+	nonce    uint64
+	value    *big.Int
+	gas      uint64
+	gasPrice *big.Int
+	input    string
+	logs     []*simLog
+
+	// block is nil while transaction is pending.
+	block *simBlock
+	index int
+}
+
+type simLog struct {
+	address string
+	topics  []string
+	data    string
+}
+
+// NewBackend starts Backend with genesis block allocating balances to accounts.
+// Close must be called when Backend is no longer needed.
+func NewBackend(alloc map[string]*big.Int) *Backend {
+	b := &Backend{
+		Server:  NewServer(),
+		genesis: time.Now().Truncate(time.Second),
+		state:   make(simState),
+		txs:     make(map[string]*simTx),
+		filters: make(map[string]*filter),
+	}
+
+	for address, balance := range alloc {
+		b.state[normalizeAddress(address)] = &simAccount{balance: new(big.Int).Set(balance)}
+	}
+
+	b.blocks = []*simBlock{b.newBlock(zeroHash, 0, nil)}
+	b.register()
+
+	return b
+}
+
+// Transfer sends value transfer and returns its hash. Transaction is pending until Commit.
+func (b *Backend) Transfer(from, to string, value *big.Int) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tx, err := b.send(&sendArgs{From: from, To: to, Value: (*hexBig)(value)})
+	if err != nil {
+		return "", err
+	}
+
+	return tx.hash, nil
+}
+
+// AddLog makes pending transaction emit log.
+func (b *Backend) AddLog(txHash, address string, topics []string, data string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tx, ok := b.txs[strings.ToLower(txHash)]
+	if !ok || tx.block != nil {
+		return fmt.Errorf("getblocktest: no pending transaction %s", txHash)
+	}
+
+	normalized := make([]string, len(topics))
+	for i, t := range topics {
+		normalized[i] = strings.ToLower(t)
+	}
+
+	if data == "" {
+		data = "0x"
+	}
+
+	tx.logs = append(tx.logs, &simLog{address: normalizeAddress(address), topics: normalized, data: data})
+
+	return nil
+}
+
+// Commit includes pending transactions into new block and returns its hash.
+func (b *Backend) Commit() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	head := b.head()
+	block := b.newBlock(head.hash, head.number+1, b.pending)
+	b.pending = nil
+	b.blocks = append(b.blocks, block)
+	b.notifyBlock(block, false)
+
+	return block.hash
+}
+
+// Reorg replaces last depth blocks with empty blocks of the same height. Transactions of replaced
+// blocks are returned to pending pool, those no longer valid are dropped. Log filters receive
+// removed logs of replaced blocks.
+func (b *Backend) Reorg(depth int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if depth <= 0 || depth >= len(b.blocks) {
+		return fmt.Errorf("getblocktest: invalid reorg depth %d at block %d", depth, b.head().number)
+	}
+
+	b.reorgs++
+
+	fork := len(b.blocks) - depth
+	orphaned := b.blocks[fork:]
+	b.blocks = b.blocks[:fork]
+
+	var txs []*simTx
+	for i := len(orphaned) - 1; i >= 0; i-- {
+		b.notifyBlock(orphaned[i], true)
+	}
+	for _, block := range orphaned {
+		txs = append(txs, block.txs...)
+	}
+	txs = append(txs, b.pending...)
+
+	b.state = b.head().state.copy()
+	for range orphaned {
+		head := b.head()
+		block := b.newBlock(head.hash, head.number+1, nil)
+		b.blocks = append(b.blocks, block)
+		b.notifyBlock(block, false)
+	}
+
+	b.pending = nil
+	for _, tx := range txs {
+		tx.block = nil
+		if err := b.apply(tx); err != nil {
+			delete(b.txs, tx.hash)
+			continue
+		}
+
+		b.pending = append(b.pending, tx)
+	}
+
+	return nil
+}
+
+func (b *Backend) head() *simBlock {
+	return b.blocks[len(b.blocks)-1]
+}
+
+// newBlock creates block with txs and state of pending pool.
+func (b *Backend) newBlock(parent string, number uint64, txs []*simTx) *simBlock {
+	block := &simBlock{number: number, parent: parent, txs: txs, state: b.state.copy()}
+
+	parts := []string{parent, strconv.FormatUint(number, 10), strconv.Itoa(b.reorgs)}
+	for i, tx := range txs {
+		tx.block = block
+		tx.index = i
+		parts = append(parts, tx.hash)
+	}
+	block.hash = keccak(strings.Join(parts, ":"))
+
+	return block
+}
+
+// send validates transaction against pending state and adds it to pending pool.
+func (b *Backend) send(args *sendArgs) (*simTx, error) {
+	if args.From == "" || args.To == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: "only value transfers with from and to are supported"}
+	}
+
+	from := normalizeAddress(args.From)
+	tx := &simTx{
+		from:     from,
+		to:       normalizeAddress(args.To),
+		nonce:    b.state.account(from).nonce,
+		value:    new(big.Int),
+		gas:      transferGas,
+		gasPrice: new(big.Int).Set(DefaultGasPrice),
+		input:    "0x",
+	}
+
+	if args.Nonce != nil {
+		tx.nonce = uint64(*args.Nonce)
+	}
+	if args.Value != nil {
+		tx.value.Set((*big.Int)(args.Value))
+	}
+	if args.Gas != nil {
+		tx.gas = uint64(*args.Gas)
+	}
+	if args.GasPrice != nil {
+		tx.gasPrice.Set((*big.Int)(args.GasPrice))
+	}
+	if args.Input != "" {
+		tx.input = args.Input
+	} else if args.Data != "" {
+		tx.input = args.Data
+	}
+
+	tx.hash = keccak(strings.Join([]string{tx.from, strconv.FormatUint(tx.nonce, 10), tx.to, tx.value.String(), tx.gasPrice.String(), tx.input}, ":"))
+
+	if err := b.submit(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// submit applies transaction to pending state and adds it to pending pool.
+func (b *Backend) submit(tx *simTx) error {
+	if _, ok := b.txs[tx.hash]; ok {
+		return &Error{Code: CodeServerError, Message: "already known"}
+	}
+
+	if err := b.apply(tx); err != nil {
+		return err
+	}
+
+	b.pending = append(b.pending, tx)
+	b.txs[tx.hash] = tx
+	for _, f := range b.filters {
+		if f.kind == pendingTransactionFilter {
+			f.changes = append(f.changes, tx.hash)
+		}
+	}
+
+	return nil
+}
+
+// apply applies transaction to pending state.
+func (b *Backend) apply(tx *simTx) error {
+	if tx.gas < transferGas {
+		return &Error{Code: CodeServerError, Message: "intrinsic gas too low"}
+	}
+
+	from := b.state.account(tx.from)
+	switch {
+	case tx.nonce < from.nonce:
+		return &Error{Code: CodeServerError, Message: "nonce too low"}
+	case tx.nonce > from.nonce:
+		return &Error{Code: CodeServerError, Message: "nonce too high"}
+	}
+
+	cost := new(big.Int).Mul(tx.gasPrice, big.NewInt(transferGas))
+	cost.Add(cost, tx.value)
+	if from.balance.Cmp(cost) < 0 {
+		return &Error{Code: CodeServerError, Message: "insufficient funds for gas * price + value"}
+	}
+
+	from.balance.Sub(from.balance, cost)
+	from.nonce++
+	to := b.state.account(tx.to)
+	to.balance.Add(to.balance, tx.value)
+
+	return nil
+}
+
+func (s simState) account(address string) *simAccount {
+	a, ok := s[address]
+	if !ok {
+		a = &simAccount{balance: new(big.Int)}
+		s[address] = a
+	}
+
+	return a
+}
+
+func (s simState) copy() simState {
+	out := make(simState, len(s))
+	for address, a := range s {
+		out[address] = &simAccount{balance: new(big.Int).Set(a.balance), nonce: a.nonce}
+	}
+
+	return out
+}
+
+// register registers handlers of supported methods on Server.
+func (b *Backend) register() {
+	handlers := map[string]func(*Call) (interface{}, error){
+		"web3_clientVersion": func(*Call) (interface{}, error) {
+			return "getblocktest/v1.0.0", nil
+		},
+		"net_version": func(*Call) (interface{}, error) {
+			return strconv.Itoa(ChainID), nil
+		},
+		"eth_chainId": func(*Call) (interface{}, error) {
+			return hexUint(ChainID), nil
+		},
+		"eth_syncing": func(*Call) (interface{}, error) {
+			return false, nil
+		},
+		"rpc_modules": func(*Call) (interface{}, error) {
+			return map[string]string{"eth": "1.0", "net": "1.0", "rpc": "1.0", "web3": "1.0"}, nil
+		},
+		"eth_accounts":                         b.accounts,
+		"eth_blockNumber":                      b.blockNumber,
+		"eth_gasPrice":                         b.gasPrice,
+		"eth_maxPriorityFeePerGas":             b.gasPrice,
+		"eth_estimateGas":                      b.estimateGas,
+		"eth_getBalance":                       b.getBalance,
+		"eth_getTransactionCount":              b.getTransactionCount,
+		"eth_getCode":                          b.getCode,
+		"eth_getStorageAt":                     b.getStorageAt,
+		"eth_call":                             b.call,
+		"eth_getBlockByNumber":                 b.getBlockByNumber,
+		"eth_getBlockByHash":                   b.getBlockByHash,
+		"eth_getBlockTransactionCountByNumber": b.getBlockTransactionCountByNumber,
+		"eth_getBlockTransactionCountByHash":   b.getBlockTransactionCountByHash,
+		"eth_sendTransaction":                  b.sendTransaction,
+		"eth_sendRawTransaction":               b.sendRawTransaction,
+		"eth_getTransactionByHash":             b.getTransactionByHash,
+		"eth_getTransactionReceipt":            b.getTransactionReceipt,
+		"eth_getLogs":                          b.getLogs,
+		"eth_newFilter":                        b.newFilter,
+		"eth_newBlockFilter":                   b.newBlockFilter,
+		"eth_newPendingTransactionFilter":      b.newPendingTransactionFilter,
+		"eth_getFilterChanges":                 b.getFilterChanges,
+		"eth_getFilterLogs":                    b.getFilterLogs,
+		"eth_uninstallFilter":                  b.uninstallFilter,
+	}
+
+	for method, h := range handlers {
+		h := h
+		b.Handle(method, func(call *Call) (interface{}, error) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			return h(call)
+		})
+	}
+}
+
+func (b *Backend) accounts(*Call) (interface{}, error) {
+	accounts := []string{}
+	for address, a := range b.state {
+		if a.balance.Sign() > 0 {
+			accounts = append(accounts, address)
+		}
+	}
+
+	return accounts, nil
+}
+
+func (b *Backend) blockNumber(*Call) (interface{}, error) {
+	return hexUint(b.head().number), nil
+}
+
+func (b *Backend) gasPrice(*Call) (interface{}, error) {
+	return hexBigString(DefaultGasPrice), nil
+}
+
+func (b *Backend) estimateGas(*Call) (interface{}, error) {
+	return hexUint(transferGas), nil
+}
+
+func (b *Backend) getBalance(call *Call) (interface{}, error) {
+	state, address, err := b.stateAt(call)
+	if err != nil {
+		return nil, err
+	}
+
+	a, ok := state[address]
+	if !ok {
+		return "0x0", nil
+	}
+
+	return hexBigString(a.balance), nil
+}
+
+func (b *Backend) getTransactionCount(call *Call) (interface{}, error) {
+	state, address, err := b.stateAt(call)
+	if err != nil {
+		return nil, err
+	}
+
+	a, ok := state[address]
+	if !ok {
+		return "0x0", nil
+	}
+
+	return hexUint(a.nonce), nil
+}
+
+func (b *Backend) getCode(call *Call) (interface{}, error) {
+	if _, _, err := b.stateAt(call); err != nil {
+		return nil, err
+	}
+
+	return "0x", nil
+}
+
+// getStorageAt returns zero value, as accounts have no storage.
+func (b *Backend) getStorageAt(call *Call) (interface{}, error) {
+	var address, slot string
+	if err := call.Param(0, &address); err != nil {
+		return nil, invalidParams(err)
+	}
+	if err := call.Param(1, &slot); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	if _, err := b.stateAtParam(call, 2); err != nil {
+		return nil, err
+	}
+
+	return zeroHash, nil
+}
+
+// call returns empty output, as accounts have no code.
+func (b *Backend) call(call *Call) (interface{}, error) {
+	var args sendArgs
+	if err := call.Param(0, &args); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	if _, err := b.stateAtParam(call, 1); err != nil {
+		return nil, err
+	}
+
+	return "0x", nil
+}
+
+// stateAt returns state at block given by second param and address given by first one.
+func (b *Backend) stateAt(call *Call) (simState, string, error) {
+	var address string
+	if err := call.Param(0, &address); err != nil {
+		return nil, "", invalidParams(err)
+	}
+
+	state, err := b.stateAtParam(call, 1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return state, normalizeAddress(address), nil
+}
+
+// stateAtParam returns state at block given by i-th param, latest block if it is missing.
+func (b *Backend) stateAtParam(call *Call, i int) (simState, error) {
+	tag := "latest"
+	if len(call.Params) > i {
+		if err := call.Param(i, &tag); err != nil {
+			return nil, invalidParams(err)
+		}
+	}
+
+	if tag == "pending" {
+		return b.state, nil
+	}
+
+	block, err := b.blockByTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, &Error{Code: CodeServerError, Message: "header not found"}
+	}
+
+	return block.state, nil
+}
+
+// blockByTag returns canonical block by number or tag, nil if it does not exist.
+func (b *Backend) blockByTag(tag string) (*simBlock, error) {
+	switch tag {
+	case "latest", "pending", "safe", "finalized":
+		return b.head(), nil
+	case "earliest":
+		return b.blocks[0], nil
+	}
+
+	n, err := parseHexUint(tag)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+
+	if n >= uint64(len(b.blocks)) {
+		return nil, nil
+	}
+
+	return b.blocks[n], nil
+}
+
+func (b *Backend) blockByHash(hash string) *simBlock {
+	hash = strings.ToLower(hash)
+	for _, block := range b.blocks {
+		if block.hash == hash {
+			return block
+		}
+	}
+
+	return nil
+}
+
+func (b *Backend) getBlockByNumber(call *Call) (interface{}, error) {
+	var tag string
+	var full bool
+	if err := call.Param(0, &tag); err != nil {
+		return nil, invalidParams(err)
+	}
+	if len(call.Params) > 1 {
+		if err := call.Param(1, &full); err != nil {
+			return nil, invalidParams(err)
+		}
+	}
+
+	block, err := b.blockByTag(tag)
+	if err != nil || block == nil {
+		return nil, err
+	}
+
+	return b.marshalBlock(block, full), nil
+}
+
+func (b *Backend) getBlockByHash(call *Call) (interface{}, error) {
+	var hash string
+	var full bool
+	if err := call.Param(0, &hash); err != nil {
+		return nil, invalidParams(err)
+	}
+	if len(call.Params) > 1 {
+		if err := call.Param(1, &full); err != nil {
+			return nil, invalidParams(err)
+		}
+	}
+
+	block := b.blockByHash(hash)
+	if block == nil {
+		return nil, nil
+	}
+
+	return b.marshalBlock(block, full), nil
+}
+
+func (b *Backend) getBlockTransactionCountByNumber(call *Call) (interface{}, error) {
+	var tag string
+	if err := call.Param(0, &tag); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	block, err := b.blockByTag(tag)
+	if err != nil || block == nil {
+		return nil, err
+	}
+
+	return hexUint(uint64(len(block.txs))), nil
+}
+
+func (b *Backend) getBlockTransactionCountByHash(call *Call) (interface{}, error) {
+	var hash string
+	if err := call.Param(0, &hash); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	block := b.blockByHash(hash)
+	if block == nil {
+		return nil, nil
+	}
+
+	return hexUint(uint64(len(block.txs))), nil
+}
+
+func (b *Backend) sendTransaction(call *Call) (interface{}, error) {
+	var args sendArgs
+	if err := call.Param(0, &args); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	tx, err := b.send(&args)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.hash, nil
+}
+
+// sendRawTransaction accepts signed legacy transactions, either replay-protected with ChainID or not.
+func (b *Backend) sendRawTransaction(call *Call) (interface{}, error) {
+	var data string
+	if err := call.Param(0, &data); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+
+	tx, err := decodeRawTx(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.submit(tx); err != nil {
+		return nil, err
+	}
+
+	return tx.hash, nil
+}
+
+// decodeRawTx decodes signed legacy transaction and recovers its sender.
+func decodeRawTx(raw []byte) (*simTx, error) {
+	if len(raw) > 0 && raw[0] < 0xc0 {
+		return nil, &Error{Code: CodeInvalidParams, Message: "only legacy transactions are supported"}
+	}
+
+	item, err := rlp.Decode(raw)
+	if err != nil || !item.IsList || len(item.List) != 9 {
+		return nil, &Error{Code: CodeInvalidParams, Message: "invalid transaction encoding"}
+	}
+
+	fields := make([][]byte, len(item.List))
+	for i, field := range item.List {
+		if field.IsList {
+			return nil, &Error{Code: CodeInvalidParams, Message: "invalid transaction encoding"}
+		}
+		fields[i] = field.Data
+	}
+
+	if len(fields[3]) != 20 {
+		return nil, &Error{Code: CodeInvalidParams, Message: "only value transfers are supported"}
+	}
+
+	nonce, gas := new(big.Int).SetBytes(fields[0]), new(big.Int).SetBytes(fields[2])
+	if !nonce.IsUint64() || !gas.IsUint64() {
+		return nil, &Error{Code: CodeInvalidParams, Message: "invalid transaction encoding"}
+	}
+
+	// v is 27 or 28 without replay protection and ChainID*2 + 35 or 36 with it (EIP-155)
+	v := new(big.Int).SetBytes(fields[6])
+	unsigned := fields[:6]
+	var recoveryID byte
+	switch {
+	case v.IsUint64() && (v.Uint64() == 27 || v.Uint64() == 28):
+		recoveryID = byte(v.Uint64() - 27)
+	case v.Cmp(big.NewInt(35)) >= 0:
+		id := new(big.Int).Sub(v, big.NewInt(35))
+		recoveryID = byte(id.Bit(0))
+		if id.Rsh(id, 1).Cmp(big.NewInt(ChainID)) != 0 {
+			return nil, &Error{Code: CodeServerError, Message: fmt.Sprintf("invalid chain id: have %s want %d", id, ChainID)}
+		}
+
+		unsigned = append(unsigned[:6:6], big.NewInt(ChainID).Bytes(), nil, nil)
+	default:
+		return nil, &Error{Code: CodeServerError, Message: "invalid transaction v, r, s values"}
+	}
+
+	from, err := recoverAddress(keccakBytes(rlp.EncodeList(unsigned...)), fields[7], fields[8], recoveryID)
+	if err != nil {
+		return nil, &Error{Code: CodeServerError, Message: "invalid transaction v, r, s values"}
+	}
+
+	return &simTx{
+		hash:     keccak(string(raw)),
+		from:     from,
+		to:       "0x" + hex.EncodeToString(fields[3]),
+		nonce:    nonce.Uint64(),
+		value:    new(big.Int).SetBytes(fields[4]),
+		gas:      gas.Uint64(),
+		gasPrice: new(big.Int).SetBytes(fields[1]),
+		input:    "0x" + hex.EncodeToString(fields[5]),
+	}, nil
+}
+
+// recoverAddress returns address of key which produced signature r, s with recovery id of hash.
+// Signatures with s in upper half of curve order are rejected as malleable (EIP-2).
+func recoverAddress(hash, r, s []byte, recoveryID byte) (string, error) {
+	if len(r) > 32 || len(s) > 32 || recoveryID > 1 {
+		return "", errInvalidSignature
+	}
+
+	var rs, ss secp256k1.ModNScalar
+	rs.SetByteSlice(r)
+	if ss.SetByteSlice(s) || ss.IsOverHalfOrder() {
+		return "", errInvalidSignature
+	}
+
+	// compact signature is recovery code followed by r and s
+	sig := make([]byte, 65)
+	sig[0] = 27 + recoveryID
+	rs.PutBytesUnchecked(sig[1:33])
+	ss.PutBytesUnchecked(sig[33:65])
+
+	pub, _, err := ecdsa.RecoverCompact(sig, hash)
+	if err != nil {
+		return "", err
+	}
+
+	return "0x" + hex.EncodeToString(keccakBytes(pub.SerializeUncompressed()[1:])[12:]), nil
+}
+
+func (b *Backend) getTransactionByHash(call *Call) (interface{}, error) {
+	var hash string
+	if err := call.Param(0, &hash); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	tx, ok := b.txs[strings.ToLower(hash)]
+	if !ok {
+		return nil, nil
+	}
+
+	return marshalTx(tx), nil
+}
+
+func (b *Backend) getTransactionReceipt(call *Call) (interface{}, error) {
+	var hash string
+	if err := call.Param(0, &hash); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	tx, ok := b.txs[strings.ToLower(hash)]
+	if !ok || tx.block == nil {
+		return nil, nil
+	}
+
+	logs := []interface{}{}
+	for _, l := range b.blockLogs(tx.block, false) {
+		if l.tx == tx {
+			logs = append(logs, l.marshal())
+		}
+	}
+
+	return map[string]interface{}{
+		"transactionHash":   tx.hash,
+		"transactionIndex":  hexUint(uint64(tx.index)),
+		"blockHash":         tx.block.hash,
+		"blockNumber":       hexUint(tx.block.number),
+		"from":              tx.from,
+		"to":                tx.to,
+		"cumulativeGasUsed": hexUint(uint64(tx.index+1) * transferGas),
+		"gasUsed":           hexUint(transferGas),
+		"effectiveGasPrice": hexBigString(tx.gasPrice),
+		"contractAddress":   nil,
+		"logs":              logs,
+		"logsBloom":         zeroBloom,
+		"status":            "0x1",
+		"type":              "0x0",
+	}, nil
+}
+
+func (b *Backend) marshalBlock(block *simBlock, full bool) map[string]interface{} {
+	txs := make([]interface{}, len(block.txs))
+	for i, tx := range block.txs {
+		if full {
+			txs[i] = marshalTx(tx)
+		} else {
+			txs[i] = tx.hash
+		}
+	}
+
+	timestamp := uint64(b.genesis.Unix()) + block.number*blockTime
+
+	return map[string]interface{}{
+		"baseFeePerGas":    "0x0",
+		"difficulty":       "0x0",
+		"extraData":        "0x",
+		"gasLimit":         hexUint(gasLimit),
+		"gasUsed":          hexUint(uint64(len(block.txs)) * transferGas),
+		"hash":             block.hash,
+		"logsBloom":        zeroBloom,
+		"miner":            zeroAddress,
+		"mixHash":          zeroHash,
+		"nonce":            "0x0000000000000000",
+		"number":           hexUint(block.number),
+		"parentHash":       block.parent,
+		"receiptsRoot":     zeroHash,
+		"sha3Uncles":       emptyUnclesHash,
+		"size":             hexUint(uint64(500 + 110*len(block.txs))),
+		"stateRoot":        zeroHash,
+		"timestamp":        hexUint(timestamp),
+		"totalDifficulty":  "0x0",
+		"transactions":     txs,
+		"transactionsRoot": zeroHash,
+		"uncles":           []interface{}{},
+	}
+}
+
+func marshalTx(tx *simTx) map[string]interface{} {
+	v := map[string]interface{}{
+		"blockHash":        nil,
+		"blockNumber":      nil,
+		"transactionIndex": nil,
+		"from":             tx.from,
+		"gas":              hexUint(tx.gas),
+		"gasPrice":         hexBigString(tx.gasPrice),
+		"hash":             tx.hash,
+		"input":            tx.input,
+		"nonce":            hexUint(tx.nonce),
+		"to":               tx.to,
+		"value":            hexBigString(tx.value),
+		"type":             "0x0",
+		"chainId":          hexUint(ChainID),
+		"v":                "0x0",
+		"r":                "0x0",
+		"s":                "0x0",
+	}
+
+	if tx.block != nil {
+		v["blockHash"] = tx.block.hash
+		v["blockNumber"] = hexUint(tx.block.number)
+		v["transactionIndex"] = hexUint(uint64(tx.index))
+	}
+
+	return v
+}
+
+// sendArgs is parameter of eth_sendTransaction.
+type sendArgs struct {
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Gas      *hexNum `json:"gas"`
+	GasPrice *hexBig `json:"gasPrice"`
+	Value    *hexBig `json:"value"`
+	Nonce    *hexNum `json:"nonce"`
+	Data     string  `json:"data"`
+	Input    string  `json:"input"`
+}
+
+type hexNum uint64
+
+func (n *hexNum) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	v, err := parseHexUint(s)
+	*n = hexNum(v)
+
+	return err
+}
+
+type hexBig big.Int
+
+func (n *hexBig) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return fmt.Errorf("invalid hex number %q", s)
+	}
+	*n = hexBig(*v)
+
+	return nil
+}
+
+func parseHexUint(s string) (uint64, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return 0, fmt.Errorf("invalid hex number %q", s)
+	}
+
+	return strconv.ParseUint(s[2:], 16, 64)
+}
+
+func hexUint(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+func hexBigString(n *big.Int) string {
+	return "0x" + n.Text(16)
+}
+
+func normalizeAddress(address string) string {
+	return strings.ToLower(address)
+}
+
+func keccak(s string) string {
+	return "0x" + hex.EncodeToString(keccakBytes([]byte(s)))
+}
+
+func keccakBytes(b []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(b)
+
+	return h.Sum(nil)
+}
+
+func invalidParams(err error) *Error {
+	return &Error{Code: CodeInvalidParams, Message: "invalid params: " + err.Error()}
+}
@@ -0,0 +1,473 @@
+package getblocktest
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/internal/rlp"
+)
+
+func TestRecoverAddress(t *testing.T) {
+	// example of EIP-155
+	hash, _ := hex.DecodeString("daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53")
+	r, _ := new(big.Int).SetString("18515461264373351373200002665853028612451056578545711640558177340181847433846", 10)
+	s, _ := new(big.Int).SetString("46948507304638947509940763649030358759909902576025900602547168820602576006531", 10)
+
+	got, err := recoverAddress(hash, r.Bytes(), s.Bytes(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f"; got != want {
+		t.Errorf("address = %s, want %s", got, want)
+	}
+
+	// s and n - s are both valid, only lower one is accepted
+	n := secp256k1.S256().N
+	if _, err := recoverAddress(hash, r.Bytes(), new(big.Int).Sub(n, s).Bytes(), 1); err == nil {
+		t.Error("signature with high s is accepted")
+	}
+}
+
+func TestBackendRawTransaction(t *testing.T) {
+	key := secp256k1.PrivKeyFromBytes([]byte{0x46, 0x46})
+	sender := "0x" + hex.EncodeToString(keccakBytes(key.PubKey().SerializeUncompressed()[1:])[12:])
+	to := "0x3535353535353535353535353535353535353535"
+
+	b := NewBackend(map[string]*big.Int{sender: big.NewInt(1e18)})
+	defer b.Close()
+	c := b.GetblockClient()
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{name: "replay-protected", raw: signTx(key, 0, to, big.NewInt(1000), ChainID)},
+		{name: "unprotected", raw: signTx(key, 1, to, big.NewInt(1000), 0)},
+		{name: "nonce too high", raw: signTx(key, 5, to, big.NewInt(1000), ChainID), wantErr: "nonce too high"},
+		{name: "other chain", raw: signTx(key, 2, to, big.NewInt(1000), 1), wantErr: "invalid chain id"},
+		{name: "insufficient funds", raw: signTx(key, 2, to, big.NewInt(2e18), ChainID), wantErr: "insufficient funds"},
+		{name: "typed transaction", raw: "0x02f86c", wantErr: "only legacy transactions"},
+		{name: "malformed", raw: "0xf8ff01", wantErr: "invalid transaction encoding"},
+		{
+			// example of EIP-155, signed for chain 1
+			name:    "EIP-155 example",
+			raw:     "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83",
+			wantErr: "invalid chain id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := c.Call(context.Background(), "eth_sendRawTransaction", tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr != "" {
+				if r.Error == nil || !strings.Contains(r.Error.Message, tt.wantErr) {
+					t.Fatalf("error = %v, want %q", r.Error, tt.wantErr)
+				}
+
+				return
+			}
+			if r.Error != nil {
+				t.Fatal(r.Error)
+			}
+
+			var hash string
+			if err := r.Decode(&hash); err != nil {
+				t.Fatal(err)
+			}
+
+			raw, _ := hex.DecodeString(tt.raw[2:])
+			if want := keccak(string(raw)); hash != want {
+				t.Errorf("hash = %s, want %s", hash, want)
+			}
+
+			b.Commit()
+
+			tx, err := getblock.CallFor[struct {
+				From string `json:"from"`
+				To   string `json:"to"`
+			}](context.Background(), c, "eth_getTransactionByHash", hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tx.From != sender || tx.To != to {
+				t.Errorf("transaction from %s to %s, want from %s to %s", tx.From, tx.To, sender, to)
+			}
+		})
+	}
+
+	balance, err := getblock.CallFor[string](context.Background(), c, "eth_getBalance", to, "latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balance != "0x7d0" {
+		t.Errorf("balance of recipient = %s, want 0x7d0", balance)
+	}
+}
+
+func TestBackendReads(t *testing.T) {
+	b := NewBackend(nil)
+	defer b.Close()
+	c := b.GetblockClient()
+
+	tests := []struct {
+		method  string
+		params  []interface{}
+		want    string
+		wantErr bool
+	}{
+		{method: "eth_call", params: []interface{}{map[string]string{"to": zeroAddress, "data": "0x70a08231"}, "latest"}, want: `"0x"`},
+		{method: "eth_call", params: []interface{}{map[string]string{"to": zeroAddress}, "0x10"}, wantErr: true},
+		{method: "eth_getStorageAt", params: []interface{}{zeroAddress, "0x0", "latest"}, want: `"` + zeroHash + `"`},
+		{method: "eth_getStorageAt", params: []interface{}{zeroAddress, "0x0", "0x10"}, wantErr: true},
+		{method: "eth_getStorageAt", params: []interface{}{zeroAddress}, wantErr: true},
+		{method: "rpc_modules", want: `{"eth":"1.0","net":"1.0","rpc":"1.0","web3":"1.0"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			r, err := c.Call(context.Background(), tt.method, tt.params...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr {
+				if r.Error == nil {
+					t.Fatalf("expected error, got %s", r.Result)
+				}
+
+				return
+			}
+			if r.Error != nil {
+				t.Fatal(r.Error)
+			}
+
+			var got interface{}
+			if err := json.Unmarshal(r.Result, &got); err != nil {
+				t.Fatal(err)
+			}
+			gotJSON, _ := json.Marshal(got)
+
+			if string(gotJSON) != tt.want {
+				t.Errorf("result = %s, want %s", gotJSON, tt.want)
+			}
+		})
+	}
+}
+
+// signTx returns signed legacy transfer with gas price DefaultGasPrice, replay-protected if chainID is not zero.
+func signTx(key *secp256k1.PrivateKey, nonce uint64, to string, value *big.Int, chainID int64) string {
+	toBytes, _ := hex.DecodeString(to[2:])
+	fields := [][]byte{
+		new(big.Int).SetUint64(nonce).Bytes(),
+		DefaultGasPrice.Bytes(),
+		big.NewInt(transferGas).Bytes(),
+		toBytes,
+		value.Bytes(),
+		nil,
+	}
+
+	unsigned := fields
+	if chainID != 0 {
+		unsigned = append(fields[:6:6], big.NewInt(chainID).Bytes(), nil, nil)
+	}
+
+	// compact signature is 27 + recovery id followed by r and s
+	sig := ecdsa.SignCompact(key, keccakBytes(rlp.EncodeList(unsigned...)), false)
+	recoveryID := int64(sig[0] - 27)
+
+	v := big.NewInt(27 + recoveryID)
+	if chainID != 0 {
+		v = big.NewInt(chainID*2 + 35 + recoveryID)
+	}
+
+	fields = append(fields, v.Bytes(), new(big.Int).SetBytes(sig[1:33]).Bytes(), new(big.Int).SetBytes(sig[33:]).Bytes())
+
+	return "0x" + hex.EncodeToString(rlp.EncodeList(fields...))
+}
+
+const (
+	alice = "0x1111111111111111111111111111111111111111"
+	bob   = "0x2222222222222222222222222222222222222222"
+	topic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+)
+
+// callFor sends call to b and decodes result, failing test on error.
+func callFor[T any](t *testing.T, b *Backend, method string, params ...interface{}) T {
+	t.Helper()
+
+	v, err := getblock.CallFor[T](context.Background(), b.GetblockClient(), method, params...)
+	if err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+
+	return v
+}
+
+func TestBackendCommit(t *testing.T) {
+	b := NewBackend(map[string]*big.Int{alice: big.NewInt(1e18)})
+	defer b.Close()
+
+	hash, err := b.Transfer(alice, bob, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// transfer is applied to pending state only
+	if got := callFor[string](t, b, "eth_getBalance", bob, "latest"); got != "0x0" {
+		t.Errorf("latest balance = %s, want 0x0", got)
+	}
+	if got := callFor[string](t, b, "eth_getBalance", bob, "pending"); got != "0x3e8" {
+		t.Errorf("pending balance = %s, want 0x3e8", got)
+	}
+	if got := callFor[map[string]interface{}](t, b, "eth_getTransactionReceipt", hash); got != nil {
+		t.Errorf("receipt of pending transaction = %v", got)
+	}
+
+	blockHash := b.Commit()
+
+	if got := callFor[string](t, b, "eth_blockNumber"); got != "0x1" {
+		t.Errorf("block number = %s, want 0x1", got)
+	}
+	if got := callFor[string](t, b, "eth_getBalance", bob, "latest"); got != "0x3e8" {
+		t.Errorf("latest balance = %s, want 0x3e8", got)
+	}
+	if got := callFor[string](t, b, "eth_getBalance", bob, "0x0"); got != "0x0" {
+		t.Errorf("balance at genesis = %s, want 0x0", got)
+	}
+	if got := callFor[string](t, b, "eth_getTransactionCount", alice, "latest"); got != "0x1" {
+		t.Errorf("nonce = %s, want 0x1", got)
+	}
+
+	block := callFor[struct {
+		Hash         string   `json:"hash"`
+		Number       string   `json:"number"`
+		Transactions []string `json:"transactions"`
+	}](t, b, "eth_getBlockByNumber", "latest", false)
+	if block.Hash != blockHash || block.Number != "0x1" || len(block.Transactions) != 1 || block.Transactions[0] != hash {
+		t.Errorf("block = %+v, want block 0x1 %s with transaction %s", block, blockHash, hash)
+	}
+
+	receipt := callFor[map[string]interface{}](t, b, "eth_getTransactionReceipt", hash)
+	if receipt["blockHash"] != blockHash || receipt["status"] != "0x1" {
+		t.Errorf("receipt = %v", receipt)
+	}
+
+	if err := b.AddLog(hash, alice, nil, ""); err == nil {
+		t.Error("log is added to mined transaction")
+	}
+
+	// empty block is committed too
+	b.Commit()
+	if got := callFor[string](t, b, "eth_getBlockTransactionCountByNumber", "latest"); got != "0x0" {
+		t.Errorf("transaction count = %s, want 0x0", got)
+	}
+}
+
+func TestBackendReorg(t *testing.T) {
+	b := NewBackend(map[string]*big.Int{alice: big.NewInt(1e18)})
+	defer b.Close()
+
+	logFilter := callFor[string](t, b, "eth_newFilter", map[string]interface{}{"address": alice})
+	blockFilter := callFor[string](t, b, "eth_newBlockFilter")
+
+	hash, err := b.Transfer(alice, bob, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddLog(hash, alice, []string{topic}, "0x01"); err != nil {
+		t.Fatal(err)
+	}
+
+	orphaned := b.Commit()
+	b.Commit()
+	callFor[[]interface{}](t, b, "eth_getFilterChanges", logFilter)
+	callFor[[]interface{}](t, b, "eth_getFilterChanges", blockFilter)
+
+	if err := b.Reorg(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := callFor[string](t, b, "eth_blockNumber"); got != "0x2" {
+		t.Errorf("block number = %s, want 0x2", got)
+	}
+
+	block := callFor[struct {
+		Hash         string   `json:"hash"`
+		Transactions []string `json:"transactions"`
+	}](t, b, "eth_getBlockByNumber", "0x1", false)
+	if block.Hash == orphaned || len(block.Transactions) != 0 {
+		t.Errorf("block 0x1 = %+v, want new empty block", block)
+	}
+
+	if got := callFor[map[string]interface{}](t, b, "eth_getBlockByHash", orphaned, false); got != nil {
+		t.Errorf("orphaned block = %v", got)
+	}
+
+	// transaction is returned to pending pool
+	if got := callFor[map[string]interface{}](t, b, "eth_getTransactionReceipt", hash); got != nil {
+		t.Errorf("receipt of orphaned transaction = %v", got)
+	}
+	if got := callFor[string](t, b, "eth_getBalance", bob, "pending"); got != "0x3e8" {
+		t.Errorf("pending balance = %s, want 0x3e8", got)
+	}
+	if got := callFor[string](t, b, "eth_getBalance", bob, "latest"); got != "0x0" {
+		t.Errorf("latest balance = %s, want 0x0", got)
+	}
+
+	logs := callFor[[]map[string]interface{}](t, b, "eth_getFilterChanges", logFilter)
+	if len(logs) != 1 || logs[0]["removed"] != true || logs[0]["blockHash"] != orphaned {
+		t.Errorf("log changes = %v, want removed log of %s", logs, orphaned)
+	}
+
+	if blocks := callFor[[]string](t, b, "eth_getFilterChanges", blockFilter); len(blocks) != 2 {
+		t.Errorf("block changes = %v, want 2 new blocks", blocks)
+	}
+
+	// transaction is mined again
+	b.Commit()
+	receipt := callFor[map[string]interface{}](t, b, "eth_getTransactionReceipt", hash)
+	if receipt["blockNumber"] != "0x3" {
+		t.Errorf("receipt = %v, want transaction mined in block 0x3", receipt)
+	}
+
+	for _, depth := range []int{0, -1, 4} {
+		if err := b.Reorg(depth); err == nil {
+			t.Errorf("reorg of depth %d succeeded", depth)
+		}
+	}
+}
+
+func TestBackendLogs(t *testing.T) {
+	b := NewBackend(map[string]*big.Int{alice: big.NewInt(1e18)})
+	defer b.Close()
+
+	other := "0x" + strings.Repeat("ab", 32)
+
+	first, _ := b.Transfer(alice, bob, big.NewInt(1))
+	if err := b.AddLog(first, alice, []string{topic, other}, "0x01"); err != nil {
+		t.Fatal(err)
+	}
+	block1 := b.Commit()
+
+	second, _ := b.Transfer(alice, bob, big.NewInt(1))
+	if err := b.AddLog(second, bob, []string{"0x" + strings.ToUpper(topic[2:])}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddLog("0x"+strings.Repeat("00", 32), bob, nil, ""); err == nil {
+		t.Error("log is added to unknown transaction")
+	}
+	b.Commit()
+
+	tests := []struct {
+		name     string
+		criteria map[string]interface{}
+		want     []string
+	}{
+		{name: "all", criteria: map[string]interface{}{"fromBlock": "earliest"}, want: []string{first, second}},
+		{name: "latest by default", criteria: map[string]interface{}{}, want: []string{second}},
+		{name: "address", criteria: map[string]interface{}{"fromBlock": "0x0", "address": alice}, want: []string{first}},
+		{name: "address list", criteria: map[string]interface{}{"fromBlock": "0x0", "address": []string{bob, "0x" + strings.ToUpper(alice[2:])}}, want: []string{first, second}},
+		{name: "topic", criteria: map[string]interface{}{"fromBlock": "0x0", "topics": []interface{}{topic}}, want: []string{first, second}},
+		{name: "second topic", criteria: map[string]interface{}{"fromBlock": "0x0", "topics": []interface{}{nil, other}}, want: []string{first}},
+		{name: "topic alternatives", criteria: map[string]interface{}{"fromBlock": "0x0", "topics": []interface{}{[]string{other, topic}}}, want: []string{first, second}},
+		{name: "range", criteria: map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x1"}, want: []string{first}},
+		{name: "range beyond head", criteria: map[string]interface{}{"fromBlock": "0x3", "toBlock": "0x10"}, want: nil},
+		{name: "block hash", criteria: map[string]interface{}{"blockHash": block1}, want: []string{first}},
+		{name: "unknown block hash", criteria: map[string]interface{}{"blockHash": other}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := callFor[[]struct {
+				TransactionHash string `json:"transactionHash"`
+			}](t, b, "eth_getLogs", tt.criteria)
+
+			var got []string
+			for _, l := range logs {
+				got = append(got, l.TransactionHash)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("logs of transactions %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	r, err := b.GetblockClient().Call(context.Background(), "eth_getLogs", map[string]interface{}{"address": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Error == nil || r.Error.Code != CodeInvalidParams {
+		t.Errorf("error = %v, want invalid params", r.Error)
+	}
+}
+
+func TestBackendFilters(t *testing.T) {
+	b := NewBackend(map[string]*big.Int{alice: big.NewInt(1e18)})
+	defer b.Close()
+
+	logFilter := callFor[string](t, b, "eth_newFilter", map[string]interface{}{"topics": []interface{}{topic}})
+	blockFilter := callFor[string](t, b, "eth_newBlockFilter")
+	txFilter := callFor[string](t, b, "eth_newPendingTransactionFilter")
+
+	hash, _ := b.Transfer(alice, bob, big.NewInt(1))
+	if err := b.AddLog(hash, alice, []string{topic}, "0x01"); err != nil {
+		t.Fatal(err)
+	}
+	blockHash := b.Commit()
+
+	if got := callFor[[]string](t, b, "eth_getFilterChanges", txFilter); !reflect.DeepEqual(got, []string{hash}) {
+		t.Errorf("pending transaction changes = %v, want %s", got, hash)
+	}
+	if got := callFor[[]string](t, b, "eth_getFilterChanges", blockFilter); !reflect.DeepEqual(got, []string{blockHash}) {
+		t.Errorf("block changes = %v, want %s", got, blockHash)
+	}
+	if got := callFor[[]map[string]interface{}](t, b, "eth_getFilterChanges", logFilter); len(got) != 1 || got[0]["transactionHash"] != hash {
+		t.Errorf("log changes = %v, want log of %s", got, hash)
+	}
+
+	// changes are drained by eth_getFilterChanges, eth_getFilterLogs returns all logs
+	for _, id := range []string{txFilter, blockFilter, logFilter} {
+		if got := callFor[[]interface{}](t, b, "eth_getFilterChanges", id); len(got) != 0 {
+			t.Errorf("changes of filter %s = %v, want none", id, got)
+		}
+	}
+	if got := callFor[[]interface{}](t, b, "eth_getFilterLogs", logFilter); len(got) != 1 {
+		t.Errorf("filter logs = %v, want 1 log", got)
+	}
+
+	c := b.GetblockClient()
+	if _, err := getblock.CallFor[[]interface{}](context.Background(), c, "eth_getFilterLogs", blockFilter); err == nil {
+		t.Error("eth_getFilterLogs of block filter succeeded")
+	}
+
+	if !callFor[bool](t, b, "eth_uninstallFilter", logFilter) {
+		t.Error("filter is not uninstalled")
+	}
+	if callFor[bool](t, b, "eth_uninstallFilter", logFilter) {
+		t.Error("filter is uninstalled twice")
+	}
+
+	var rpcErr *getblock.RPCError
+	if _, err := getblock.CallFor[[]interface{}](context.Background(), c, "eth_getFilterChanges", logFilter); !errors.As(err, &rpcErr) {
+		t.Errorf("error = %v, want filter not found", err)
+	}
+}
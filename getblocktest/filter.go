@@ -0,0 +1,318 @@
+package getblocktest
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type filterKind int
+
+const (
+	logFilter filterKind = iota
+	blockFilter
+	pendingTransactionFilter
+)
+
+type filter struct {
+	kind     filterKind
+	criteria *filterCriteria
+	// changes is log objects or hashes since last eth_getFilterChanges.
+	changes []interface{}
+}
+
+// filterCriteria is parameter of eth_getLogs and eth_newFilter.
+type filterCriteria struct {
+	FromBlock string            `json:"fromBlock"`
+	ToBlock   string            `json:"toBlock"`
+	BlockHash string            `json:"blockHash"`
+	Address   json.RawMessage   `json:"address"`
+	Topics    []json.RawMessage `json:"topics"`
+
+	addresses []string
+	topics    [][]string
+}
+
+// parse decodes address and topics which can be single value, list or null.
+func (c *filterCriteria) parse() error {
+	var err error
+	if c.addresses, err = stringOrList(c.Address); err != nil {
+		return err
+	}
+
+	for _, raw := range c.Topics {
+		topics, err := stringOrList(raw)
+		if err != nil {
+			return err
+		}
+
+		c.topics = append(c.topics, topics)
+	}
+
+	return nil
+}
+
+func (c *filterCriteria) matches(l *blockLog) bool {
+	if len(c.addresses) != 0 && !contains(c.addresses, l.log.address) {
+		return false
+	}
+
+	if len(c.topics) > len(l.log.topics) {
+		return false
+	}
+
+	for i, topics := range c.topics {
+		if len(topics) != 0 && !contains(topics, l.log.topics[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var list []string
+	if raw[0] == '[' {
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+	} else {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		list = []string{s}
+	}
+
+	for i := range list {
+		list[i] = strings.ToLower(list[i])
+	}
+
+	return list, nil
+}
+
+// blockLog is log emitted in block.
+type blockLog struct {
+	log     *simLog
+	tx      *simTx
+	block   *simBlock
+	index   int
+	removed bool
+}
+
+func (l *blockLog) marshal() map[string]interface{} {
+	return map[string]interface{}{
+		"address":          l.log.address,
+		"topics":           l.log.topics,
+		"data":             l.log.data,
+		"blockNumber":      hexUint(l.block.number),
+		"blockHash":        l.block.hash,
+		"transactionHash":  l.tx.hash,
+		"transactionIndex": hexUint(uint64(l.tx.index)),
+		"logIndex":         hexUint(uint64(l.index)),
+		"removed":          l.removed,
+	}
+}
+
+// blockLogs returns logs emitted in block.
+func (b *Backend) blockLogs(block *simBlock, removed bool) []*blockLog {
+	var logs []*blockLog
+	for _, tx := range block.txs {
+		for _, l := range tx.logs {
+			logs = append(logs, &blockLog{log: l, tx: tx, block: block, index: len(logs), removed: removed})
+		}
+	}
+
+	return logs
+}
+
+// notifyBlock delivers block added to or removed from canonical chain to filters.
+func (b *Backend) notifyBlock(block *simBlock, removed bool) {
+	for _, f := range b.filters {
+		switch f.kind {
+		case blockFilter:
+			if !removed {
+				f.changes = append(f.changes, block.hash)
+			}
+		case logFilter:
+			for _, l := range b.blockLogs(block, removed) {
+				if f.criteria.matches(l) && f.criteria.inRange(b, block.number) {
+					f.changes = append(f.changes, l.marshal())
+				}
+			}
+		}
+	}
+}
+
+// inRange reports whether block number n is within block range of criteria.
+func (c *filterCriteria) inRange(b *Backend, n uint64) bool {
+	from, to := c.bounds(b)
+
+	return n >= from && n <= to
+}
+
+// bounds returns block range of criteria, range of open bounds is unlimited.
+func (c *filterCriteria) bounds(b *Backend) (uint64, uint64) {
+	bound := func(tag string, open uint64) uint64 {
+		if tag == "" || tag == "latest" || tag == "pending" || tag == "safe" || tag == "finalized" {
+			return open
+		}
+
+		block, err := b.blockByTag(tag)
+		if err != nil {
+			return open
+		}
+		if block == nil {
+			n, _ := strconv.ParseUint(strings.TrimPrefix(tag, "0x"), 16, 64)
+			return n
+		}
+
+		return block.number
+	}
+
+	return bound(c.FromBlock, 0), bound(c.ToBlock, ^uint64(0))
+}
+
+// logs returns canonical logs matching criteria.
+func (b *Backend) logs(c *filterCriteria) []interface{} {
+	blocks := b.blocks
+	if c.BlockHash != "" {
+		block := b.blockByHash(c.BlockHash)
+		if block == nil {
+			return []interface{}{}
+		}
+
+		blocks = []*simBlock{block}
+	} else {
+		from, to := c.bounds(b)
+		if c.FromBlock == "" || c.FromBlock == "latest" {
+			from = b.head().number
+		}
+		if to > b.head().number {
+			to = b.head().number
+		}
+		if from > to {
+			return []interface{}{}
+		}
+
+		blocks = b.blocks[from : to+1]
+	}
+
+	logs := []interface{}{}
+	for _, block := range blocks {
+		for _, l := range b.blockLogs(block, false) {
+			if c.matches(l) {
+				logs = append(logs, l.marshal())
+			}
+		}
+	}
+
+	return logs
+}
+
+func (b *Backend) getLogs(call *Call) (interface{}, error) {
+	c, err := criteriaParam(call)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.logs(c), nil
+}
+
+func (b *Backend) newFilter(call *Call) (interface{}, error) {
+	c, err := criteriaParam(call)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.addFilter(&filter{kind: logFilter, criteria: c}), nil
+}
+
+func (b *Backend) newBlockFilter(*Call) (interface{}, error) {
+	return b.addFilter(&filter{kind: blockFilter}), nil
+}
+
+func (b *Backend) newPendingTransactionFilter(*Call) (interface{}, error) {
+	return b.addFilter(&filter{kind: pendingTransactionFilter}), nil
+}
+
+func (b *Backend) addFilter(f *filter) string {
+	b.filterID++
+	id := hexUint(b.filterID)
+	b.filters[id] = f
+
+	return id
+}
+
+func (b *Backend) getFilterChanges(call *Call) (interface{}, error) {
+	f, err := b.filterParam(call)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := f.changes
+	if changes == nil {
+		changes = []interface{}{}
+	}
+	f.changes = nil
+
+	return changes, nil
+}
+
+func (b *Backend) getFilterLogs(call *Call) (interface{}, error) {
+	f, err := b.filterParam(call)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.kind != logFilter {
+		return nil, &Error{Code: CodeServerError, Message: "filter not found"}
+	}
+
+	return b.logs(f.criteria), nil
+}
+
+func (b *Backend) uninstallFilter(call *Call) (interface{}, error) {
+	var id string
+	if err := call.Param(0, &id); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	_, ok := b.filters[id]
+	delete(b.filters, id)
+
+	return ok, nil
+}
+
+func (b *Backend) filterParam(call *Call) (*filter, error) {
+	var id string
+	if err := call.Param(0, &id); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	f, ok := b.filters[id]
+	if !ok {
+		return nil, &Error{Code: CodeServerError, Message: "filter not found"}
+	}
+
+	return f, nil
+}
+
+func criteriaParam(call *Call) (*filterCriteria, error) {
+	c := &filterCriteria{}
+	if len(call.Params) > 0 {
+		if err := call.Param(0, c); err != nil {
+			return nil, invalidParams(err)
+		}
+	}
+
+	if err := c.parse(); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	return c, nil
+}
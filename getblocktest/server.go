@@ -0,0 +1,378 @@
+// Package getblocktest provides fake JSON-RPC server for testing code using getblock clients.
+package getblocktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/ofen/getblock-go"
+)
+
+// JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeServerError    = -32000
+)
+
+// Token is token of clients created by Server.GetblockClient.
+const Token = "getblocktest-token"
+
+// Server is fake JSON-RPC 2.0 server supporting batch requests. Calls of methods without handler
+// fail with method not found error. Notifications (calls without id) are handled but not answered.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	faults   []*Fault
+	calls    []*Call
+}
+
+// Call is JSON-RPC call received by Server.
+type Call struct {
+	Method string
+	Params []json.RawMessage
+	// Header is HTTP headers of request which contained call.
+	Header http.Header
+	// Batch is true if call was part of batch request.
+	Batch bool
+}
+
+// Param decodes i-th param into v.
+func (c *Call) Param(i int, v interface{}) error {
+	if i >= len(c.Params) {
+		return fmt.Errorf("getblocktest: %s: missing param %d", c.Method, i)
+	}
+
+	return json.Unmarshal(c.Params[i], v)
+}
+
+// HandlerFunc handles call returning its result. Error of type *Error is returned as is,
+// other errors are returned as server error.
+type HandlerFunc func(call *Call) (interface{}, error)
+
+// Error is JSON-RPC error.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// Fault is failure injected into responses of Server.
+type Fault struct {
+	// Method limits fault to requests containing call of method, empty means any request.
+	Method string
+	// Times is number of requests fault applies to, zero means every request.
+	Times int
+	// Latency delays response.
+	Latency time.Duration
+	// Status is HTTP status code of response. Body of response is empty for statuses other than 200.
+	Status int
+	// Error replaces result of matching calls.
+	Error *Error
+	// Drop closes connection without response.
+	Drop bool
+}
+
+// NewServer starts Server. Close must be called when Server is no longer needed.
+func NewServer() *Server {
+	s := &Server{handlers: make(map[string]HandlerFunc)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// GetblockClient creates getblock client of Server. Client of embedded httptest.Server is still
+// available as Server.Client.
+func (s *Server) GetblockClient(opts ...getblock.Option) *getblock.Client {
+	return getblock.New(Token, s.URL, opts...)
+}
+
+// Handle registers handler of method.
+func (s *Server) Handle(method string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method] = h
+}
+
+// Respond registers canned result of method.
+func (s *Server) Respond(method string, result interface{}) {
+	s.Handle(method, func(*Call) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// RespondError registers canned JSON-RPC error of method.
+func (s *Server) RespondError(method string, code int, message string) {
+	s.Handle(method, func(*Call) (interface{}, error) {
+		return nil, &Error{Code: code, Message: message}
+	})
+}
+
+// Inject adds fault. Faults are applied in order they were added.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// Calls returns calls received by Server, all calls if no methods are given.
+func (s *Server) Calls(methods ...string) []*Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var calls []*Call
+	for _, c := range s.calls {
+		if len(methods) == 0 || contains(methods, c.Method) {
+			calls = append(calls, c)
+		}
+	}
+
+	return calls
+}
+
+// Reset removes handlers, faults and received calls.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers = make(map[string]HandlerFunc)
+	s.faults = nil
+	s.calls = nil
+}
+
+type request struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	batch := len(body) > 0 && body[0] == '['
+
+	var requests []*request
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		var req *request
+		err = json.Unmarshal(body, &req)
+		requests = []*request{req}
+	}
+
+	if err != nil {
+		writeJSON(w, http.StatusOK, &response{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &Error{Code: CodeParseError, Message: "parse error"},
+		})
+		return
+	}
+
+	if len(requests) == 0 {
+		writeJSON(w, http.StatusOK, &response{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &Error{Code: CodeInvalidRequest, Message: "invalid request"},
+		})
+		return
+	}
+
+	// calls has nil for invalid requests, they are answered with error and not recorded
+	calls := make([]*Call, len(requests))
+	var valid []*Call
+	for i, req := range requests {
+		if req == nil || req.Method == "" {
+			continue
+		}
+
+		calls[i] = &Call{Method: req.Method, Params: req.Params, Header: r.Header.Clone(), Batch: batch}
+		valid = append(valid, calls[i])
+	}
+
+	faults := s.receive(valid)
+
+	var status int
+	for _, f := range faults {
+		if f.Latency > 0 {
+			select {
+			case <-time.After(f.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if f.Drop {
+			if hj, ok := w.(http.Hijacker); ok {
+				if conn, _, err := hj.Hijack(); err == nil {
+					conn.Close()
+				}
+			}
+
+			return
+		}
+
+		if f.Status != 0 && status == 0 {
+			status = f.Status
+		}
+	}
+
+	if status != 0 && status != http.StatusOK {
+		w.WriteHeader(status)
+		return
+	}
+
+	// notifications are handled without response
+	var responses []*response
+	for i, req := range requests {
+		resp := s.handle(req, calls[i], faults)
+		if calls[i] != nil && req.ID == nil {
+			continue
+		}
+
+		responses = append(responses, resp)
+	}
+
+	switch {
+	case len(responses) == 0:
+		w.WriteHeader(http.StatusOK)
+	case batch:
+		writeJSON(w, http.StatusOK, responses)
+	default:
+		writeJSON(w, http.StatusOK, responses[0])
+	}
+}
+
+// receive records calls and returns faults applying to them.
+func (s *Server) receive(calls []*Call) []*Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, calls...)
+
+	var faults []*Fault
+	active := s.faults[:0]
+	for _, f := range s.faults {
+		if matches(f, calls) {
+			faults = append(faults, f)
+			if f.Times > 0 {
+				f.Times--
+				if f.Times == 0 {
+					continue
+				}
+			}
+		}
+
+		active = append(active, f)
+	}
+	s.faults = active
+
+	return faults
+}
+
+func (s *Server) handle(req *request, call *Call, faults []*Fault) *response {
+	resp := &response{JSONRPC: "2.0", ID: json.RawMessage("null")}
+	if req != nil && req.ID != nil {
+		resp.ID = req.ID
+	}
+
+	if call == nil {
+		resp.Error = &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+		return resp
+	}
+
+	for _, f := range faults {
+		if f.Error != nil && (f.Method == "" || f.Method == call.Method) {
+			resp.Error = f.Error
+			return resp
+		}
+	}
+
+	s.mu.Lock()
+	h, ok := s.handlers[call.Method]
+	s.mu.Unlock()
+
+	if !ok {
+		resp.Error = &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("the method %s does not exist/is not available", call.Method)}
+		return resp
+	}
+
+	result, err := h(call)
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeServerError, Message: err.Error()}
+		}
+
+		resp.Error = rpcErr
+		return resp
+	}
+
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	resp.Result = result
+
+	return resp
+}
+
+func matches(f *Fault, calls []*Call) bool {
+	if f.Method == "" {
+		return true
+	}
+
+	for _, c := range calls {
+		if c.Method == f.Method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,125 @@
+package getblocktest_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestServerInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		// want is error codes of responses, zero means result.
+		want      []int
+		wantBatch bool
+		wantCalls int
+	}{
+		{name: "call", body: `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`, want: []int{0}, wantCalls: 1},
+		{name: "null", body: `null`, want: []int{getblocktest.CodeInvalidRequest}},
+		{name: "malformed", body: `{"jsonrpc"`, want: []int{getblocktest.CodeParseError}},
+		{name: "empty batch", body: `[]`, want: []int{getblocktest.CodeInvalidRequest}},
+		{name: "notification", body: `{"jsonrpc":"2.0","method":"eth_blockNumber"}`, wantCalls: 1},
+		{name: "null id", body: `{"jsonrpc":"2.0","id":null,"method":"eth_blockNumber"}`, want: []int{0}, wantCalls: 1},
+		{
+			name:      "batch with notification",
+			body:      `[{"jsonrpc":"2.0","method":"eth_blockNumber"},{"jsonrpc":"2.0","id":2,"method":"eth_chainId"}]`,
+			want:      []int{getblocktest.CodeMethodNotFound},
+			wantBatch: true,
+			wantCalls: 2,
+		},
+		{
+			name:      "batch of notifications",
+			body:      `[{"jsonrpc":"2.0","method":"eth_blockNumber"},{"jsonrpc":"2.0","method":"eth_chainId"}]`,
+			wantCalls: 2,
+		},
+		{
+			name:      "invalid request without id",
+			body:      `[{"jsonrpc":"2.0"}]`,
+			want:      []int{getblocktest.CodeInvalidRequest},
+			wantBatch: true,
+		},
+		{
+			name:      "null batch element",
+			body:      `[{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"},null]`,
+			want:      []int{0, getblocktest.CodeInvalidRequest},
+			wantBatch: true,
+			wantCalls: 1,
+		},
+		{
+			name:      "first batch element null",
+			body:      `[null,{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber"}]`,
+			want:      []int{getblocktest.CodeInvalidRequest, 0},
+			wantBatch: true,
+			wantCalls: 1,
+		},
+		{
+			name:      "batch element without method",
+			body:      `[{"jsonrpc":"2.0","id":1}]`,
+			want:      []int{getblocktest.CodeInvalidRequest},
+			wantBatch: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			s.Respond("eth_blockNumber", "0x1")
+
+			resp, err := http.Post(s.URL, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			type response struct {
+				Result json.RawMessage     `json:"result"`
+				Error  *getblocktest.Error `json:"error"`
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// notifications are not answered
+			var responses []response
+			switch {
+			case len(body) == 0:
+			case tt.wantBatch:
+				err = json.Unmarshal(body, &responses)
+			default:
+				var r response
+				err = json.Unmarshal(body, &r)
+				responses = []response{r}
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(responses) != len(tt.want) {
+				t.Fatalf("got %d responses, want %d", len(responses), len(tt.want))
+			}
+
+			for i, r := range responses {
+				code := 0
+				if r.Error != nil {
+					code = r.Error.Code
+				}
+
+				if code != tt.want[i] {
+					t.Errorf("response %d: error code %d, want %d", i, code, tt.want[i])
+				}
+			}
+
+			if n := len(s.Calls()); n != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
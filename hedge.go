@@ -0,0 +1,206 @@
+package getblock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// hedgeWindow is number of recent latencies of method used to compute hedging delay.
+	hedgeWindow = 100
+	// hedgeMinSamples is number of latencies of method observed before its calls are hedged.
+	hedgeMinSamples = 20
+)
+
+// hedgedMethods are known read-only methods which are safe to send to several endpoints.
+// Filter methods are not listed as filters exist only on endpoint which created them.
+var hedgedMethods = []string{
+	"eth_blockNumber",
+	"eth_call",
+	"eth_chainId",
+	"eth_estimateGas",
+	"eth_feeHistory",
+	"eth_gasPrice",
+	"eth_getBalance",
+	"eth_getBlockByHash",
+	"eth_getBlockByNumber",
+	"eth_getBlockReceipts",
+	"eth_getBlockTransactionCountByHash",
+	"eth_getBlockTransactionCountByNumber",
+	"eth_getCode",
+	"eth_getLogs",
+	"eth_getProof",
+	"eth_getStorageAt",
+	"eth_getTransactionByBlockHashAndIndex",
+	"eth_getTransactionByBlockNumberAndIndex",
+	"eth_getTransactionByHash",
+	"eth_getTransactionCount",
+	"eth_getTransactionReceipt",
+	"eth_getUncleByBlockHashAndIndex",
+	"eth_getUncleByBlockNumberAndIndex",
+	"eth_getUncleCountByBlockHash",
+	"eth_getUncleCountByBlockNumber",
+	"eth_maxPriorityFeePerGas",
+	"eth_syncing",
+	"net_version",
+	"web3_clientVersion",
+	"trace_block",
+	"trace_call",
+	"trace_filter",
+	"trace_get",
+	"trace_replayBlockTransactions",
+	"trace_transaction",
+	"debug_traceBlockByNumber",
+	"debug_traceCall",
+	"debug_traceTransaction",
+}
+
+// WithHedging enables hedged requests for clients with several endpoints. If attempt of call has not
+// completed within percentile (e.g. 0.95) of recent latencies of the method, duplicate request is sent
+// to another endpoint. First successful response is returned and other request is canceled.
+// Only known read-only methods like eth_call and eth_getBlockByNumber are hedged, except methods listed in exclude.
+func WithHedging(percentile float64, exclude ...string) Option {
+	return func(c *Client) {
+		h := &hedger{
+			percentile: percentile,
+			methods:    make(map[string]bool),
+			latencies:  make(map[string]*latencyWindow),
+		}
+		for _, m := range hedgedMethods {
+			h.methods[m] = true
+		}
+		for _, m := range exclude {
+			delete(h.methods, m)
+		}
+
+		c.hedger = h
+	}
+}
+
+type hedger struct {
+	percentile float64
+	// methods are methods which are hedged.
+	methods map[string]bool
+
+	mu        sync.Mutex
+	latencies map[string]*latencyWindow
+}
+
+// latencyWindow is ring buffer of recent latencies.
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+type hedgeResult struct {
+	r   *Response
+	err error
+}
+
+// middleware sends duplicate request if first one is slower than hedging delay of method.
+func (h *hedger) middleware(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+		if !h.methods[req.Method] || callOptionsFromContext(ctx).noRetry {
+			return next.Handle(ctx, req)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan hedgeResult, 2)
+		send := func(ctx context.Context) {
+			start := time.Now()
+			r, err := next.Handle(ctx, req)
+			if err == nil {
+				h.observe(req.Method, time.Since(start))
+			}
+
+			results <- hedgeResult{r: r, err: err}
+		}
+
+		go send(ctx)
+		pending := 1
+
+		delay, ok := h.delay(req.Method)
+		var hedge <-chan time.Time
+		if ok {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			hedge = timer.C
+		}
+
+		var last hedgeResult
+		for pending > 0 {
+			select {
+			case <-hedge:
+				hedge = nil
+				go send(context.WithValue(ctx, hedgeContextKey{}, true))
+				pending++
+			case last = <-results:
+				pending--
+				// Failure before hedging is left to Retry.
+				if last.err == nil || hedge != nil {
+					return last.r, last.err
+				}
+			}
+		}
+
+		return last.r, last.err
+	})
+}
+
+// delay returns hedging delay of method, false if not enough latencies were observed.
+func (h *hedger) delay(method string) (time.Duration, bool) {
+	h.mu.Lock()
+	w, ok := h.latencies[method]
+	if !ok || len(w.samples) < hedgeMinSamples {
+		h.mu.Unlock()
+		return 0, false
+	}
+
+	samples := append([]time.Duration(nil), w.samples...)
+	h.mu.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+
+	i := int(h.percentile * float64(len(samples)))
+	if i >= len(samples) {
+		i = len(samples) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+
+	return samples[i], true
+}
+
+func (h *hedger) observe(method string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w, ok := h.latencies[method]
+	if !ok {
+		w = &latencyWindow{}
+		h.latencies[method] = w
+	}
+
+	if len(w.samples) < hedgeWindow {
+		w.samples = append(w.samples, d)
+		return
+	}
+
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % hedgeWindow
+}
+
+type hedgeContextKey struct{}
+
+// isHedge reports whether ctx is context of duplicate request sent by hedging.
+func isHedge(ctx context.Context) bool {
+	hedge, _ := ctx.Value(hedgeContextKey{}).(bool)
+	return hedge
+}
@@ -0,0 +1,151 @@
+package getblock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestHedging(t *testing.T) {
+	const latency = 300 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		method  string
+		exclude []string
+		ctx     func(context.Context) context.Context
+		hedged  bool
+	}{
+		{name: "read", method: "eth_blockNumber", hedged: true},
+		{name: "write", method: "eth_sendRawTransaction"},
+		{name: "unknown method", method: "custom_method"},
+		{name: "excluded", method: "eth_blockNumber", exclude: []string{"eth_blockNumber"}},
+		{name: "no retry", method: "eth_blockNumber", ctx: getblock.WithNoRetry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slow, fast := getblocktest.NewServer(), getblocktest.NewServer()
+			defer slow.Close()
+			defer fast.Close()
+			slow.Respond(tt.method, "0x1")
+			fast.Respond(tt.method, "0x1")
+
+			c := getblock.NewPool([]getblock.Endpoint{
+				{URL: slow.URL, Token: getblocktest.Token},
+				{URL: fast.URL, Token: getblocktest.Token},
+			}, getblock.WithHedging(0.9, tt.exclude...))
+			defer c.Close()
+
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(ctx)
+			}
+
+			// collect enough latencies to compute hedging delay
+			for i := 0; i < 50; i++ {
+				if _, err := c.Call(ctx, tt.method); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			slow.Inject(getblocktest.Fault{Latency: latency})
+			fast.Reset()
+			fast.Respond(tt.method, "0x1")
+
+			// round robin sends first attempt of one of calls to slow endpoint
+			start := time.Now()
+			for i := 0; i < 2; i++ {
+				if _, err := c.Call(ctx, tt.method); err != nil {
+					t.Fatal(err)
+				}
+			}
+			elapsed := time.Since(start)
+
+			if hedged := elapsed < latency; hedged != tt.hedged {
+				t.Errorf("hedged = %v (calls took %v), want %v", hedged, elapsed, tt.hedged)
+			}
+
+			// hedged duplicate of slow call goes to fast endpoint, slow fast call can be hedged too
+			if tt.hedged && len(fast.Calls()) < 2 {
+				t.Errorf("fast endpoint got %d calls, want at least 2", len(fast.Calls()))
+			}
+		})
+	}
+}
+
+func TestHedgingCallInfo(t *testing.T) {
+	slow, fast := getblocktest.NewServer(), getblocktest.NewServer()
+	defer slow.Close()
+	defer fast.Close()
+	slow.Respond("eth_blockNumber", "0x1")
+	fast.Respond("eth_blockNumber", "0x1")
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	m := getblock.NewMetrics()
+	c := getblock.NewPool([]getblock.Endpoint{
+		{URL: slow.URL, Token: getblocktest.Token},
+		{URL: fast.URL, Token: getblocktest.Token},
+	}, getblock.WithHedging(0.9), getblock.WithTracerProvider(tp), getblock.WithMetrics(m))
+	defer c.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 50; i++ {
+		if _, err := c.Call(ctx, "eth_blockNumber"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	slow.Inject(getblocktest.Fault{Latency: 300 * time.Millisecond})
+	exporter.Reset()
+
+	// round robin sends first attempt of one of calls to slow endpoint
+	for i := 0; i < 4; i++ {
+		if _, err := c.Call(ctx, "eth_blockNumber"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// hedged duplicates are not retries
+	var hedged int
+	for _, span := range exporter.GetSpans() {
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes {
+			attrs[kv.Key] = kv.Value
+		}
+
+		if v := attrs["getblock.retry_count"]; v != attribute.IntValue(0) {
+			t.Errorf("retry_count = %v, want 0", v.Emit())
+		}
+
+		if v, ok := attrs["getblock.hedge_count"]; ok {
+			hedged++
+			if v != attribute.IntValue(1) {
+				t.Errorf("hedge_count = %v, want 1", v.Emit())
+			}
+		}
+	}
+
+	if hedged == 0 {
+		t.Error("no span has hedge_count")
+	}
+
+	if n := testutil.CollectAndCount(m, "getblock_retries_total"); n != 0 {
+		t.Errorf("got %d retries_total series, want 0", n)
+	}
+
+	if n := testutil.CollectAndCount(m, "getblock_hedges_total"); n != 1 {
+		t.Errorf("got %d hedges_total series, want 1", n)
+	}
+}
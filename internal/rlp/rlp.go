@@ -0,0 +1,147 @@
+// Package rlp implements Recursive Length Prefix encoding of Ethereum.
+package rlp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var errTooShort = errors.New("rlp: value size exceeds available input length")
+
+// Item is decoded RLP item, either byte string or list of items.
+type Item struct {
+	IsList bool
+	Data   []byte
+	List   []Item
+}
+
+// Decode decodes single RLP item which must span entire input.
+func Decode(b []byte) (Item, error) {
+	item, rest, err := decodeItem(b)
+	if err != nil {
+		return Item{}, err
+	}
+
+	if len(rest) != 0 {
+		return Item{}, fmt.Errorf("rlp: %d trailing bytes after value", len(rest))
+	}
+
+	return item, nil
+}
+
+func decodeItem(b []byte) (Item, []byte, error) {
+	isList, content, rest, err := split(b)
+	if err != nil {
+		return Item{}, nil, err
+	}
+
+	if !isList {
+		return Item{Data: content}, rest, nil
+	}
+
+	item := Item{IsList: true}
+	for len(content) != 0 {
+		var elem Item
+		elem, content, err = decodeItem(content)
+		if err != nil {
+			return Item{}, nil, err
+		}
+		item.List = append(item.List, elem)
+	}
+
+	return item, rest, nil
+}
+
+// split splits first RLP item of b into its content and remaining input.
+func split(b []byte) (isList bool, content []byte, rest []byte, err error) {
+	if len(b) == 0 {
+		return false, nil, nil, errTooShort
+	}
+
+	prefix := b[0]
+	switch {
+	case prefix < 0x80:
+		return false, b[:1], b[1:], nil
+	case prefix < 0xb8:
+		content, rest, err = take(b[1:], uint64(prefix-0x80))
+		if err == nil && len(content) == 1 && content[0] < 0x80 {
+			err = errors.New("rlp: non-canonical single byte string")
+		}
+		return false, content, rest, err
+	case prefix < 0xc0:
+		size, tail, err := readSize(b[1:], int(prefix-0xb7))
+		if err != nil {
+			return false, nil, nil, err
+		}
+		content, rest, err = take(tail, size)
+		return false, content, rest, err
+	case prefix < 0xf8:
+		content, rest, err = take(b[1:], uint64(prefix-0xc0))
+		return true, content, rest, err
+	default:
+		size, tail, err := readSize(b[1:], int(prefix-0xf7))
+		if err != nil {
+			return false, nil, nil, err
+		}
+		content, rest, err = take(tail, size)
+		return true, content, rest, err
+	}
+}
+
+// readSize reads big endian size of n bytes.
+func readSize(b []byte, n int) (uint64, []byte, error) {
+	if len(b) < n {
+		return 0, nil, errTooShort
+	}
+
+	if b[0] == 0 {
+		return 0, nil, errors.New("rlp: non-canonical size information")
+	}
+
+	var size uint64
+	for _, c := range b[:n] {
+		size = size<<8 | uint64(c)
+	}
+
+	if size < 56 {
+		return 0, nil, errors.New("rlp: non-canonical size information")
+	}
+
+	return size, b[n:], nil
+}
+
+func take(b []byte, size uint64) ([]byte, []byte, error) {
+	if size > uint64(len(b)) {
+		return nil, nil, errTooShort
+	}
+
+	return b[:size], b[size:], nil
+}
+
+// EncodeList encodes list of byte strings.
+func EncodeList(items ...[]byte) []byte {
+	var content []byte
+	for _, item := range items {
+		if len(item) == 1 && item[0] < 0x80 {
+			content = append(content, item[0])
+			continue
+		}
+
+		content = append(content, header(0x80, len(item))...)
+		content = append(content, item...)
+	}
+
+	return append(header(0xc0, len(content)), content...)
+}
+
+// header returns prefix of string (offset 0x80) or list (offset 0xc0) of size bytes.
+func header(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+
+	n := big.NewInt(int64(size)).Bytes()
+
+	return append([]byte{offset + 55 + byte(len(n))}, n...)
+}
@@ -0,0 +1,187 @@
+package getblock
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultLogBodyLimit is default maximum size of logged request and response bodies.
+const defaultLogBodyLimit = 1024
+
+// sensitiveHeaders are HTTP headers which values are never logged.
+var sensitiveHeaders = []string{
+	authorizationHeaderKey,
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// WithLogger enables structured logging of HTTP requests sent to endpoints and their responses.
+// Tokens are redacted from URLs, headers, bodies and error strings.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevels sets level of successful requests and level of failed ones, i.e. requests
+// ended with transport error, HTTP error or JSON-RPC error. Defaults are slog.LevelDebug and slog.LevelWarn.
+func WithLogLevels(ok, failed slog.Level) Option {
+	return func(c *Client) {
+		c.logLevel = ok
+		c.logFailedLevel = failed
+	}
+}
+
+// WithLogBodyLimit sets maximum size of logged request and response bodies, longer bodies are truncated.
+// Zero disables logging of bodies. Default is 1024 bytes.
+func WithLogBodyLimit(n int) Option {
+	return func(c *Client) {
+		c.logBodyLimit = n
+	}
+}
+
+// logTransport logs HTTP requests and responses.
+type logTransport struct {
+	base http.RoundTripper
+	c    *Client
+}
+
+func (c *Client) withLogTransport(hc *http.Client) *http.Client {
+	base := hc.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	out := *hc
+	out.Transport = &logTransport{base: base, c: c}
+
+	return &out
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	c := t.c
+	if !c.logger.Enabled(ctx, c.logLevel) && !c.logger.Enabled(ctx, c.logFailedLevel) {
+		return t.base.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	attrs := []slog.Attr{
+		slog.String("method", rpcMethod(reqBody)),
+		slog.String("url", c.redact(req.URL.String())),
+		slog.Any("request_headers", c.logHeader(req.Header)),
+	}
+	if c.logBodyLimit > 0 {
+		attrs = append(attrs, slog.String("request_body", c.logBody(reqBody)))
+	}
+
+	failed := err != nil
+	if err != nil {
+		attrs = append(attrs, slog.String("error", c.redact(err.Error())))
+	} else {
+		respBody, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr == nil {
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		} else {
+			// Caller still gets read error after data received so far.
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(respBody), errReader{readErr}))
+			attrs = append(attrs, slog.String("error", c.redact(readErr.Error())))
+		}
+
+		failed = readErr != nil || resp.StatusCode >= 400 || hasRPCError(respBody)
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.Any("response_headers", c.logHeader(resp.Header)),
+		)
+		if c.logBodyLimit > 0 {
+			attrs = append(attrs, slog.String("response_body", c.logBody(respBody)))
+		}
+	}
+
+	attrs = append(attrs, slog.Duration("duration", elapsed))
+
+	level := c.logLevel
+	if failed {
+		level = c.logFailedLevel
+	}
+
+	c.logger.LogAttrs(ctx, level, "getblock: rpc request", attrs...)
+
+	return resp, err
+}
+
+// logHeader returns header with sensitive values redacted.
+func (c *Client) logHeader(h http.Header) slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for k, v := range h {
+		value := c.redact(v[0])
+		for _, s := range sensitiveHeaders {
+			if http.CanonicalHeaderKey(s) == k {
+				value = redacted
+			}
+		}
+		attrs = append(attrs, slog.String(k, value))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// logBody returns redacted body truncated to body limit.
+func (c *Client) logBody(body []byte) string {
+	s := c.redact(string(body))
+	if len(s) > c.logBodyLimit {
+		s = s[:c.logBodyLimit] + "...(" + strconv.Itoa(len(s)-c.logBodyLimit) + " bytes truncated)"
+	}
+
+	return s
+}
+
+// rpcMethod returns method of JSON-RPC request body.
+func rpcMethod(body []byte) string {
+	var req struct {
+		Method string `json:"method"`
+	}
+	json.Unmarshal(body, &req)
+
+	return req.Method
+}
+
+// hasRPCError reports whether JSON-RPC response body contains error.
+func hasRPCError(body []byte) bool {
+	var resp struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return false
+	}
+
+	return len(resp.Error) != 0 && string(resp.Error) != "null"
+}
+
+// errReader is reader failing with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
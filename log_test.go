@@ -0,0 +1,156 @@
+package getblock_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []getblock.Option
+		method string
+		setup  func(s *getblocktest.Server)
+		// want is expected values of attributes of logged record.
+		want map[string]interface{}
+		// absent is attributes which must not be logged.
+		absent []string
+	}{
+		{
+			name:   "success",
+			method: "eth_blockNumber",
+			setup:  func(s *getblocktest.Server) { s.Respond("eth_blockNumber", "0x1") },
+			want: map[string]interface{}{
+				"level":         "DEBUG",
+				"method":        "eth_blockNumber",
+				"status":        float64(http.StatusOK),
+				"response_body": `{"jsonrpc":"2.0","id":1,"result":"0x1"}` + "\n",
+			},
+			absent: []string{"error"},
+		},
+		{
+			name:   "rpc error",
+			method: "eth_call",
+			setup: func(s *getblocktest.Server) {
+				s.RespondError("eth_call", getblocktest.CodeServerError, "execution reverted")
+			},
+			want: map[string]interface{}{"level": "WARN", "method": "eth_call", "status": float64(http.StatusOK)},
+		},
+		{
+			name:   "http error",
+			method: "eth_blockNumber",
+			setup:  func(s *getblocktest.Server) { s.Inject(getblocktest.Fault{Status: http.StatusTooManyRequests}) },
+			want:   map[string]interface{}{"level": "WARN", "status": float64(http.StatusTooManyRequests)},
+		},
+		{
+			name:   "transport error",
+			method: "eth_blockNumber",
+			setup:  func(s *getblocktest.Server) { s.Inject(getblocktest.Fault{Drop: true}) },
+			want:   map[string]interface{}{"level": "WARN", "method": "eth_blockNumber"},
+			absent: []string{"status", "response_body"},
+		},
+		{
+			name:   "custom levels",
+			opts:   []getblock.Option{getblock.WithLogLevels(slog.LevelInfo, slog.LevelError)},
+			method: "eth_call",
+			setup: func(s *getblocktest.Server) {
+				s.RespondError("eth_call", getblocktest.CodeServerError, "execution reverted")
+			},
+			want: map[string]interface{}{"level": "ERROR"},
+		},
+		{
+			name:   "truncated body",
+			opts:   []getblock.Option{getblock.WithLogBodyLimit(10)},
+			method: "eth_blockNumber",
+			setup:  func(s *getblocktest.Server) { s.Respond("eth_blockNumber", "0x1") },
+			want: map[string]interface{}{
+				"request_body":  `{"jsonrpc"...(53 bytes truncated)`,
+				"response_body": `{"jsonrpc"...(30 bytes truncated)`,
+			},
+		},
+		{
+			name:   "bodies disabled",
+			opts:   []getblock.Option{getblock.WithLogBodyLimit(0)},
+			method: "eth_blockNumber",
+			setup:  func(s *getblocktest.Server) { s.Respond("eth_blockNumber", "0x1") },
+			want:   map[string]interface{}{"level": "DEBUG"},
+			absent: []string{"request_body", "response_body"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			tt.setup(s)
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			opts := append([]getblock.Option{getblock.WithLogger(logger), getblock.WithRetry(1)}, tt.opts...)
+			c := s.GetblockClient(opts...)
+			c.Call(context.Background(), tt.method)
+
+			if strings.Contains(buf.String(), getblocktest.Token) {
+				t.Errorf("log contains token: %s", buf.String())
+			}
+
+			var record map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("log is not single JSON record: %v: %s", err, buf.String())
+			}
+
+			if record["msg"] != "getblock: rpc request" {
+				t.Errorf("msg = %v", record["msg"])
+			}
+
+			for k, v := range tt.want {
+				if record[k] != v {
+					t.Errorf("%s = %#v, want %#v", k, record[k], v)
+				}
+			}
+
+			for _, k := range tt.absent {
+				if v, ok := record[k]; ok {
+					t.Errorf("%s = %#v, want absent", k, v)
+				}
+			}
+
+			headers, _ := record["request_headers"].(map[string]interface{})
+			if v := headers["X-Api-Key"]; v != "***" {
+				t.Errorf("request header X-Api-Key = %v, want ***", v)
+			}
+		})
+	}
+}
+
+func TestLoggerDisabled(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_blockNumber", "0x1")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// requests logged below handler level are skipped
+	r, err := s.GetblockClient(getblock.WithLogger(logger)).Call(context.Background(), "eth_blockNumber")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(r.Result) != `"0x1"` {
+		t.Errorf("result = %s", r.Result)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("logged %s", buf.String())
+	}
+}
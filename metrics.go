@@ -0,0 +1,125 @@
+package getblock
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is Prometheus collector of Client calls. It exposes:
+//
+//	getblock_requests_total{method,status}       calls by outcome: "ok", "rpc_error", HTTP status code or "error"
+//	getblock_request_duration_seconds{method}    call latency including retries
+//	getblock_retries_total{method}               attempts repeated after failure
+//	getblock_hedges_total{method}                duplicate requests sent by hedging
+//	getblock_requests_in_flight                  calls in progress
+//
+// Metrics can be shared by several clients.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	retries  *prometheus.CounterVec
+	hedges   *prometheus.CounterVec
+	inFlight prometheus.Gauge
+}
+
+// NewMetrics creates Metrics. It must be registered on prometheus.Registerer to be exposed.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "getblock",
+			Name:      "requests_total",
+			Help:      "Total number of JSON-RPC calls by method and status.",
+		}, []string{"method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "getblock",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of JSON-RPC calls including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "getblock",
+			Name:      "retries_total",
+			Help:      "Total number of repeated JSON-RPC attempts by method.",
+		}, []string{"method"}),
+		hedges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "getblock",
+			Name:      "hedges_total",
+			Help:      "Total number of hedged JSON-RPC requests by method.",
+		}, []string{"method"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "getblock",
+			Name:      "requests_in_flight",
+			Help:      "Number of JSON-RPC calls in progress.",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+	m.retries.Describe(ch)
+	m.hedges.Describe(ch)
+	m.inFlight.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+	m.retries.Collect(ch)
+	m.hedges.Collect(ch)
+	m.inFlight.Collect(ch)
+}
+
+// WithMetrics enables collecting metrics of calls into m.
+func WithMetrics(m *Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+func (m *Metrics) middleware(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		start := time.Now()
+		r, err := next.Handle(ctx, req)
+		m.duration.WithLabelValues(req.Method).Observe(time.Since(start).Seconds())
+
+		if info := callInfoFromContext(ctx); info != nil {
+			s := info.snapshot()
+			if s.attempts > 1 {
+				m.retries.WithLabelValues(req.Method).Add(float64(s.attempts - 1))
+			}
+
+			if s.hedges > 0 {
+				m.hedges.WithLabelValues(req.Method).Add(float64(s.hedges))
+			}
+		}
+
+		m.requests.WithLabelValues(req.Method, callStatus(r, err)).Inc()
+
+		return r, err
+	})
+}
+
+// callStatus returns status label of call outcome.
+func callStatus(r *Response, err error) string {
+	switch {
+	case err == nil && r != nil && r.Error != nil:
+		return "rpc_error"
+	case err == nil:
+		return "ok"
+	}
+
+	if status := httpStatus(err); status != 0 {
+		return strconv.Itoa(status)
+	}
+
+	return "error"
+}
@@ -0,0 +1,92 @@
+package getblock_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestMetrics(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_blockNumber", "0x1")
+	s.Respond("eth_getBalance", "0x0")
+	s.RespondError("eth_call", getblocktest.CodeServerError, "execution reverted")
+	s.Inject(getblocktest.Fault{Method: "eth_blockNumber", Times: 1, Status: http.StatusBadGateway})
+	s.Inject(getblocktest.Fault{Method: "eth_getBalance", Status: http.StatusTooManyRequests})
+	s.Inject(getblocktest.Fault{Method: "eth_chainId", Drop: true})
+
+	m := getblock.NewMetrics()
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(m)
+
+	c := s.GetblockClient(getblock.WithMetrics(m), getblock.WithRetry(3))
+	ctx := context.Background()
+	c.Call(ctx, "eth_blockNumber")
+	c.Call(ctx, "eth_blockNumber")
+	c.Call(ctx, "eth_call")
+	c.Call(ctx, "eth_getBalance")
+	c.Call(ctx, "eth_chainId")
+
+	want := `
+# HELP getblock_requests_total Total number of JSON-RPC calls by method and status.
+# TYPE getblock_requests_total counter
+getblock_requests_total{method="eth_blockNumber",status="ok"} 2
+getblock_requests_total{method="eth_call",status="rpc_error"} 1
+getblock_requests_total{method="eth_chainId",status="error"} 1
+getblock_requests_total{method="eth_getBalance",status="429"} 1
+# HELP getblock_retries_total Total number of repeated JSON-RPC attempts by method.
+# TYPE getblock_retries_total counter
+getblock_retries_total{method="eth_blockNumber"} 1
+getblock_retries_total{method="eth_chainId"} 2
+# HELP getblock_requests_in_flight Number of JSON-RPC calls in progress.
+# TYPE getblock_requests_in_flight gauge
+getblock_requests_in_flight 0
+`
+	if err := testutil.CollectAndCompare(m, strings.NewReader(want), "getblock_requests_total", "getblock_retries_total", "getblock_requests_in_flight"); err != nil {
+		t.Error(err)
+	}
+
+	// latency is observed once per call, including retries
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]uint64)
+	for _, f := range families {
+		if f.GetName() != "getblock_request_duration_seconds" {
+			continue
+		}
+
+		for _, metric := range f.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "method" {
+					counts[label.GetValue()] = metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+
+	wantCounts := map[string]uint64{"eth_blockNumber": 2, "eth_call": 1, "eth_chainId": 1, "eth_getBalance": 1}
+	for method, want := range wantCounts {
+		if counts[method] != want {
+			t.Errorf("latency samples of %s = %d, want %d", method, counts[method], want)
+		}
+	}
+
+	if len(counts) != len(wantCounts) {
+		t.Errorf("latency series = %v, want %v", counts, wantCounts)
+	}
+
+	if problems, err := testutil.GatherAndLint(reg); err != nil || len(problems) != 0 {
+		t.Errorf("lint problems = %v, error %v", problems, err)
+	}
+}
@@ -0,0 +1,122 @@
+package getblock
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Request is JSON-RPC request passed through middleware chain.
+type Request struct {
+	Method string
+	Params []interface{}
+	// Header is HTTP headers sent with request, they override headers set by Client.
+	Header http.Header
+}
+
+// Handler handles JSON-RPC request.
+type Handler interface {
+	Handle(ctx context.Context, req *Request) (*Response, error)
+}
+
+// HandlerFunc is function implementing Handler.
+type HandlerFunc func(ctx context.Context, req *Request) (*Response, error)
+
+// Handle calls f(ctx, req).
+func (f HandlerFunc) Handle(ctx context.Context, req *Request) (*Response, error) {
+	return f(ctx, req)
+}
+
+// Middleware wraps Handler with additional behaviour.
+type Middleware func(next Handler) Handler
+
+// Chain wraps h with middlewares. First middleware is outermost.
+func Chain(h Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+
+	return h
+}
+
+// WithMiddleware adds middlewares called once per call, before cache and retries.
+// First middleware is outermost.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithAttemptMiddleware adds middlewares called on every attempt of a call, after rate limiting.
+// First middleware is outermost.
+func WithAttemptMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.attemptMiddlewares = append(c.attemptMiddlewares, middlewares...)
+	}
+}
+
+// WithRetry sets maximum number of attempts per call, 1 disables retries. Default is 5.
+func WithRetry(attempts int) Option {
+	return func(c *Client) {
+		c.attempts = attempts
+	}
+}
+
+// Retry returns middleware repeating request on transport or 5xx error up to attempts times in total.
+// When used by Client every attempt fails over to next endpoint. Calls with WithNoRetry context are not repeated.
+func Retry(attempts int) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			ctx = contextWithAttempts(ctx)
+			if callOptionsFromContext(ctx).noRetry {
+				return next.Handle(ctx, req)
+			}
+
+			var r *Response
+			var err error
+			for i := 0; i < attempts || i == 0; i++ {
+				r, err = next.Handle(ctx, req)
+				if err == nil || !isRetryable(err) || ctx.Err() != nil {
+					break
+				}
+			}
+
+			return r, err
+		})
+	}
+}
+
+type attemptsContextKey struct{}
+
+// attempts is endpoints tried during call.
+type attempts struct {
+	mu   sync.Mutex
+	used map[*endpoint]bool
+}
+
+// next returns first of endpoints not tried yet, or first endpoint if all were tried.
+func (a *attempts) next(endpoints []*endpoint) *endpoint {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e := endpoints[0]
+	for _, candidate := range endpoints {
+		if !a.used[candidate] {
+			e = candidate
+			break
+		}
+	}
+
+	a.used[e] = true
+
+	return e
+}
+
+func contextWithAttempts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptsContextKey{}, &attempts{used: make(map[*endpoint]bool)})
+}
+
+func attemptsFromContext(ctx context.Context) *attempts {
+	a, _ := ctx.Value(attemptsContextKey{}).(*attempts)
+	return a
+}
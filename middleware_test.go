@@ -0,0 +1,169 @@
+package getblock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestChain(t *testing.T) {
+	var got []string
+	record := func(name string) getblock.Middleware {
+		return func(next getblock.Handler) getblock.Handler {
+			return getblock.HandlerFunc(func(ctx context.Context, req *getblock.Request) (*getblock.Response, error) {
+				got = append(got, name+" before")
+				r, err := next.Handle(ctx, req)
+				got = append(got, name+" after")
+
+				return r, err
+			})
+		}
+	}
+
+	h := getblock.Chain(getblock.HandlerFunc(func(ctx context.Context, req *getblock.Request) (*getblock.Response, error) {
+		got = append(got, "handler")
+		return &getblock.Response{}, nil
+	}), record("a"), record("b"))
+
+	if _, err := h.Handle(context.Background(), &getblock.Request{Method: "eth_blockNumber"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a before", "b before", "handler", "b after", "a after"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		err      error
+		ctx      func(context.Context) context.Context
+		want     int
+	}{
+		{name: "success", attempts: 3, want: 1},
+		{name: "transport error", attempts: 3, err: errors.New("connection reset"), want: 3},
+		{name: "5xx", attempts: 3, err: &getblock.HTTPError{Code: http.StatusBadGateway}, want: 3},
+		{name: "wrapped 5xx", attempts: 2, err: fmt.Errorf("attempt: %w", &getblock.HTTPError{Code: http.StatusServiceUnavailable}), want: 2},
+		{name: "single attempt", attempts: 1, err: errors.New("connection reset"), want: 1},
+		{name: "zero attempts", attempts: 0, err: errors.New("connection reset"), want: 1},
+		{name: "4xx", attempts: 3, err: &getblock.HTTPError{Code: http.StatusTooManyRequests}, want: 1},
+		{name: "rpc error", attempts: 3, err: &getblock.RPCError{Code: -32000, Message: "execution reverted"}, want: 1},
+		{name: "invalid response", attempts: 3, err: fmt.Errorf("%w: eth_blockNumber: empty response", getblock.ErrInvalidResponse), want: 1},
+		{name: "no endpoints", attempts: 3, err: getblock.ErrNoEndpoints, want: 1},
+		{name: "quota exceeded", attempts: 3, err: &getblock.QuotaExceededError{}, want: 1},
+		{name: "circuit open", attempts: 3, err: &getblock.CircuitOpenError{}, want: 1},
+		{name: "deadline exceeded", attempts: 3, err: context.DeadlineExceeded, want: 1},
+		{name: "no retry", attempts: 3, err: errors.New("connection reset"), ctx: getblock.WithNoRetry, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			h := getblock.Retry(tt.attempts)(getblock.HandlerFunc(func(ctx context.Context, req *getblock.Request) (*getblock.Response, error) {
+				calls++
+				return nil, tt.err
+			}))
+
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(ctx)
+			}
+
+			if _, err := h.Handle(ctx, &getblock.Request{Method: "eth_blockNumber"}); err != tt.err {
+				t.Errorf("error = %v, want %v", err, tt.err)
+			}
+
+			if calls != tt.want {
+				t.Errorf("got %d attempts, want %d", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+	h := getblock.Retry(5)(getblock.HandlerFunc(func(ctx context.Context, req *getblock.Request) (*getblock.Response, error) {
+		calls++
+		cancel()
+
+		return nil, errors.New("connection reset")
+	}))
+
+	if _, err := h.Handle(ctx, &getblock.Request{Method: "eth_blockNumber"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if calls != 1 {
+		t.Errorf("got %d attempts, want 1", calls)
+	}
+}
+
+func TestInvalidResponseNotRetried(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed", body: `<html>bad gateway</html>`},
+		{name: "null", body: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.Write([]byte(tt.body))
+			}))
+			defer s.Close()
+
+			c := getblock.New(getblocktest.Token, s.URL, getblock.WithRetry(3))
+			if _, err := c.Call(context.Background(), "eth_blockNumber"); !errors.Is(err, getblock.ErrInvalidResponse) {
+				t.Fatalf("error = %v, want ErrInvalidResponse", err)
+			}
+
+			if n := atomic.LoadInt32(&requests); n != 1 {
+				t.Errorf("got %d requests, want 1", n)
+			}
+		})
+	}
+}
+
+func TestAttemptMiddleware(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_blockNumber", "0x1")
+	s.Inject(getblocktest.Fault{Times: 2, Status: http.StatusBadGateway})
+
+	var calls, attempts int
+	count := func(n *int) getblock.Middleware {
+		return func(next getblock.Handler) getblock.Handler {
+			return getblock.HandlerFunc(func(ctx context.Context, req *getblock.Request) (*getblock.Response, error) {
+				*n++
+				return next.Handle(ctx, req)
+			})
+		}
+	}
+
+	c := s.GetblockClient(getblock.WithMiddleware(count(&calls)), getblock.WithAttemptMiddleware(count(&attempts)))
+	if _, err := c.Call(context.Background(), "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 1 || attempts != 3 {
+		t.Errorf("got %d calls and %d attempts, want 1 and 3", calls, attempts)
+	}
+}
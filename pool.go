@@ -0,0 +1,266 @@
+package getblock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Endpoint is JSON-RPC endpoint and its API token.
+// Token may be empty if it is part of URL or not required.
+type Endpoint struct {
+	URL   string
+	Token string
+	// Conn is connection used instead of HTTP requests to URL, e.g. WebSocket.
+	// URL is still used to identify endpoint in logs and telemetry. Headers set with WithHeader
+	// are not passed to Conn.
+	Conn Conn
+}
+
+// Policy is endpoint selection policy of Client.
+type Policy int
+
+const (
+	// RoundRobin spreads requests evenly across healthy endpoints.
+	RoundRobin Policy = iota
+	// LeastLatency sends requests to healthy endpoint with lowest observed latency.
+	LeastLatency
+)
+
+// WithPolicy sets endpoint selection policy, RoundRobin is used by default.
+func WithPolicy(p Policy) Option {
+	return func(c *Client) {
+		c.policy = p
+	}
+}
+
+// WithHealthCheck enables periodic health check calling method on every endpoint.
+// Endpoint is considered unhealthy after transport or 5xx error either in health check
+// or in regular call. Unhealthy endpoints are used only after all healthy ones failed.
+func WithHealthCheck(interval time.Duration, method string, params ...interface{}) Option {
+	return func(c *Client) {
+		c.healthCheck = healthCheck{
+			interval: interval,
+			method:   method,
+			params:   params,
+		}
+	}
+}
+
+type healthCheck struct {
+	interval time.Duration
+	method   string
+	params   []interface{}
+}
+
+type endpoint struct {
+	conn Conn
+	url  string
+	// host is endpoint host safe to expose in logs and telemetry.
+	host string
+	// circuit is nil if circuit breaker is disabled.
+	circuit *circuit
+
+	mu        sync.Mutex
+	unhealthy bool
+	latency   time.Duration
+}
+
+func newEndpoint(e Endpoint, httpClient *http.Client) *endpoint {
+	host, safeURL := "", redacted
+	if u, err := url.Parse(e.URL); err == nil {
+		host, safeURL = u.Host, redactURL(u)
+	}
+
+	conn := e.Conn
+	if conn == nil {
+		conn = &httpConn{url: e.URL, safeURL: safeURL, token: e.Token, client: httpClient}
+	}
+
+	return &endpoint{
+		conn: conn,
+		url:  e.URL,
+		host: host,
+	}
+}
+
+// Endpoints returns URLs of endpoints of Client in order they were given.
+func (c *Client) Endpoints() []string {
+	urls := make([]string, len(c.endpoints))
+	for i, e := range c.endpoints {
+		urls[i] = e.url
+	}
+
+	return urls
+}
+
+// observe updates endpoint health and moving average of latency with call outcome.
+func (e *endpoint) observe(d time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err != nil && isRetryable(err) {
+		e.unhealthy = true
+		return
+	}
+
+	e.unhealthy = false
+	if err != nil {
+		return
+	}
+
+	if e.latency == 0 {
+		e.latency = d
+	} else {
+		e.latency = (e.latency*4 + d) / 5
+	}
+}
+
+func (e *endpoint) state() (healthy bool, latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return !e.unhealthy, e.latency
+}
+
+// order returns endpoints in order they should be tried according to policy and health.
+func (c *Client) order() []*endpoint {
+	n := len(c.endpoints)
+	if n < 2 {
+		return c.endpoints
+	}
+
+	start := int(atomic.AddUint64(&c.next, 1) % uint64(n))
+
+	healthy := make([]*endpoint, 0, n)
+	latency := make(map[*endpoint]time.Duration, n)
+	var unhealthy []*endpoint
+	for i := 0; i < n; i++ {
+		e := c.endpoints[(start+i)%n]
+		ok, d := e.state()
+		if !ok {
+			unhealthy = append(unhealthy, e)
+			continue
+		}
+
+		healthy = append(healthy, e)
+		latency[e] = d
+	}
+
+	if c.policy == LeastLatency {
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return latency[healthy[i]] < latency[healthy[j]]
+		})
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// pick returns endpoint for next attempt of call preferring endpoints not tried during the call.
+// Endpoints with open circuit are skipped.
+func (c *Client) pick(ctx context.Context) (*endpoint, error) {
+	endpoints := c.order()
+	if url := callOptionsFromContext(ctx).endpoint; url != "" {
+		endpoints = withURL(endpoints, url)
+		if len(endpoints) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNoEndpoints, c.redact(url))
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	a := attemptsFromContext(ctx)
+
+	var openErr *CircuitOpenError
+	for len(endpoints) > 0 {
+		e := endpoints[0]
+		if a != nil {
+			e = a.next(endpoints)
+		}
+
+		if e.circuit == nil {
+			return e, nil
+		}
+
+		err := e.circuit.acquire(time.Now())
+		if err == nil {
+			return e, nil
+		}
+
+		if openErr == nil || err.Until.Before(openErr.Until) {
+			openErr = err
+		}
+
+		endpoints = without(endpoints, e)
+	}
+
+	return nil, openErr
+}
+
+func withURL(endpoints []*endpoint, url string) []*endpoint {
+	for _, e := range endpoints {
+		if e.url == url {
+			return []*endpoint{e}
+		}
+	}
+
+	return nil
+}
+
+func without(endpoints []*endpoint, e *endpoint) []*endpoint {
+	out := make([]*endpoint, 0, len(endpoints))
+	for _, candidate := range endpoints {
+		if candidate != e {
+			out = append(out, candidate)
+		}
+	}
+
+	return out
+}
+
+func (c *Client) runHealthChecks() {
+	ticker := time.NewTicker(c.healthCheck.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.checkHealth()
+		}
+	}
+}
+
+func (c *Client) checkHealth() {
+	data, err := json.Marshal(newRequest(0, c.healthCheck.method, c.healthCheck.params))
+	if err != nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, e := range c.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), c.healthCheck.interval)
+			defer cancel()
+
+			start := time.Now()
+			var r *Response
+			err := e.call(ctx, data, &r)
+			e.observe(time.Since(start), err)
+		}(e)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,108 @@
+package getblock_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestPoolFailover(t *testing.T) {
+	rpcErr := &getblocktest.Error{Code: getblocktest.CodeServerError, Message: "boom"}
+
+	tests := []struct {
+		name string
+		// faults are faults of each endpoint, nil means healthy endpoint.
+		faults     []*getblocktest.Fault
+		wantErr    bool
+		wantRPCErr bool
+		// wantCalls is total number of calls of all endpoints, zero means any.
+		wantCalls int
+	}{
+		{
+			name:      "healthy",
+			faults:    []*getblocktest.Fault{nil, nil},
+			wantCalls: 1,
+		},
+		{
+			name:   "5xx",
+			faults: []*getblocktest.Fault{{Status: http.StatusServiceUnavailable}, nil},
+		},
+		{
+			name:   "dropped connection",
+			faults: []*getblocktest.Fault{nil, {Drop: true}},
+		},
+		{
+			name:       "JSON-RPC error is not failed over",
+			faults:     []*getblocktest.Fault{{Error: rpcErr}, {Error: rpcErr}},
+			wantRPCErr: true,
+			wantCalls:  1,
+		},
+		{
+			name:      "all endpoints fail",
+			faults:    []*getblocktest.Fault{{Status: http.StatusBadGateway}, {Status: http.StatusServiceUnavailable}},
+			wantErr:   true,
+			wantCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var servers []*getblocktest.Server
+			var endpoints []getblock.Endpoint
+			for _, f := range tt.faults {
+				s := getblocktest.NewServer()
+				defer s.Close()
+				s.Respond("eth_blockNumber", "0x1")
+				if f != nil {
+					s.Inject(*f)
+				}
+
+				servers = append(servers, s)
+				endpoints = append(endpoints, getblock.Endpoint{URL: s.URL, Token: getblocktest.Token})
+			}
+
+			c := getblock.NewPool(endpoints, getblock.WithRetry(3))
+			defer c.Close()
+
+			r, err := c.Call(context.Background(), "eth_blockNumber")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			calls, healthyCalls := 0, 0
+			for i, s := range servers {
+				calls += len(s.Calls())
+				if tt.faults[i] == nil {
+					healthyCalls += len(s.Calls())
+				}
+			}
+
+			if tt.wantCalls != 0 && calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", calls, tt.wantCalls)
+			}
+
+			switch {
+			case tt.wantErr:
+			case tt.wantRPCErr:
+				if r.Error == nil || r.Error.Code != rpcErr.Code {
+					t.Errorf("error = %v, want %v", r.Error, rpcErr)
+				}
+			default:
+				if string(r.Result) != `"0x1"` {
+					t.Errorf("result = %s, want \"0x1\"", r.Result)
+				}
+
+				if healthyCalls != 1 {
+					t.Errorf("healthy endpoints got %d calls, want 1", healthyCalls)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package getblock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Quorum sends read to all Clients (e.g. clients of different providers) and returns result
+// as soon as Threshold of them agree. Results are compared after normalization: hex strings are
+// compared case-insensitively and null fields are ignored.
+type Quorum struct {
+	Clients   []Caller
+	Threshold int
+	// OnDisagreement is called when quorum is reached but some of responses have different
+	// result or JSON-RPC error. Failed calls are not disagreements. It is called once all clients
+	// answered, in separate goroutine if Call has already returned.
+	OnDisagreement func(*Disagreement)
+}
+
+// QuorumAnswer is answer of single client of Quorum.
+type QuorumAnswer struct {
+	// Client is index of client in Quorum.Clients.
+	Client   int
+	Response *Response
+	Err      error
+}
+
+// Disagreement is answers of Quorum clients to the same call which are not all equal.
+type Disagreement struct {
+	Method  string
+	Params  []interface{}
+	Answers []QuorumAnswer
+}
+
+// QuorumError is returned when Threshold of clients did not agree on result.
+type QuorumError struct {
+	Threshold int
+	Disagreement
+}
+
+func (e *QuorumError) Error() string {
+	return fmt.Sprintf("getblock: quorum of %d not reached for %s: %d answers", e.Threshold, e.Method, len(e.Answers))
+}
+
+// Call sends request to all clients and returns response agreed by Threshold of them.
+// Calls of clients still in progress are not canceled once quorum is reached, their answers are
+// collected in background and passed to OnDisagreement. Responses with JSON-RPC errors do not
+// count towards quorum. Non-idempotent methods like eth_sendRawTransaction are refused.
+// Threshold must be between 1 and number of Clients.
+func (q *Quorum) Call(ctx context.Context, method string, params ...interface{}) (*Response, error) {
+	if q.Threshold <= 0 || q.Threshold > len(q.Clients) {
+		return nil, fmt.Errorf("getblock: quorum: invalid threshold %d of %d clients", q.Threshold, len(q.Clients))
+	}
+
+	for _, m := range nonIdempotentMethods {
+		if m == method {
+			return nil, fmt.Errorf("getblock: quorum: %s is not a read", method)
+		}
+	}
+
+	// calls outlive ctx once quorum is reached, until then they are canceled with it
+	callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, cancel)
+
+	answers := make(chan QuorumAnswer, len(q.Clients))
+	for i, c := range q.Clients {
+		go func(i int, c Caller) {
+			r, err := c.Call(callCtx, method, params...)
+			answers <- QuorumAnswer{Client: i, Response: r, Err: err}
+		}(i, c)
+	}
+
+	d := &Disagreement{Method: method, Params: params}
+	votes := make(map[string]int)
+	for range q.Clients {
+		a := <-answers
+		d.Answers = append(d.Answers, a)
+
+		key, ok := vote(a)
+		if !ok {
+			continue
+		}
+
+		votes[key]++
+		if votes[key] >= q.Threshold {
+			stop()
+			go func() {
+				defer cancel()
+				for len(d.Answers) < len(q.Clients) {
+					d.Answers = append(d.Answers, <-answers)
+				}
+
+				q.report(d, key)
+			}()
+
+			return a.Response, nil
+		}
+	}
+
+	stop()
+	cancel()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, &QuorumError{Threshold: q.Threshold, Disagreement: *d}
+}
+
+// report calls OnDisagreement if any of answers of d differs from agreed result.
+func (q *Quorum) report(d *Disagreement, agreed string) {
+	if q.OnDisagreement == nil {
+		return
+	}
+
+	for _, a := range d.Answers {
+		if a.Err != nil || a.Response == nil {
+			continue
+		}
+
+		if key, ok := vote(a); !ok || key != agreed {
+			q.OnDisagreement(d)
+			return
+		}
+	}
+}
+
+// vote returns normalized result of successful answer.
+func vote(a QuorumAnswer) (string, bool) {
+	if a.Err != nil || a.Response == nil || a.Response.Error != nil {
+		return "", false
+	}
+
+	key, err := normalizeResult(a.Response.Result)
+	if err != nil {
+		return "", false
+	}
+
+	return key, true
+}
+
+// normalizeResult returns canonical JSON of result.
+func normalizeResult(result json.RawMessage) (string, error) {
+	var v interface{}
+	if !isNull(result) {
+		if err := decodeJSON(result, &v); err != nil {
+			return "", err
+		}
+	}
+
+	return canonical(v)
+}
+
+// canonical returns JSON of normalized v.
+func canonical(v interface{}) (string, error) {
+	data, err := json.Marshal(normalize(v))
+	return string(data), err
+}
+
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			return strings.ToLower(v)
+		}
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			if x != nil {
+				m[k] = normalize(x)
+			}
+		}
+
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, x := range v {
+			list[i] = normalize(x)
+		}
+
+		return list
+	}
+
+	return v
+}
@@ -0,0 +1,206 @@
+package getblock_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestQuorum(t *testing.T) {
+	results := []string{"0x1", "0x1", "0x2"}
+	var clients []getblock.Caller
+	for _, result := range results {
+		s := getblocktest.NewServer()
+		defer s.Close()
+		s.Respond("eth_blockNumber", result)
+		clients = append(clients, s.GetblockClient())
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		want      string
+		wantErr   bool
+	}{
+		{name: "majority", threshold: 2, want: `"0x1"`},
+		{name: "unanimous", threshold: 3, wantErr: true},
+		{name: "zero threshold", threshold: 0, wantErr: true},
+		{name: "negative threshold", threshold: -1, wantErr: true},
+		{name: "threshold above clients", threshold: 4, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &getblock.Quorum{Clients: clients, Threshold: tt.threshold}
+
+			r, err := q.Call(context.Background(), "eth_blockNumber")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", r.Result)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(r.Result) != tt.want {
+				t.Errorf("result = %s, want %s", r.Result, tt.want)
+			}
+		})
+	}
+}
+
+// callerFunc is Caller answering with function.
+type callerFunc func(ctx context.Context) (*getblock.Response, error)
+
+func (f callerFunc) Call(ctx context.Context, method string, params ...interface{}) (*getblock.Response, error) {
+	return f(ctx)
+}
+
+func answer(result string) callerFunc {
+	return func(ctx context.Context) (*getblock.Response, error) {
+		return &getblock.Response{Result: json.RawMessage(result)}, nil
+	}
+}
+
+func TestQuorumDisagreement(t *testing.T) {
+	tests := []struct {
+		name     string
+		late     *getblock.Response
+		lateErr  error
+		disagree bool
+	}{
+		{name: "late different result", late: &getblock.Response{Result: json.RawMessage(`"0x2"`)}, disagree: true},
+		{name: "late rpc error", late: &getblock.Response{Error: &getblock.RPCError{Code: -32000, Message: "header not found"}}, disagree: true},
+		{name: "late equal result", late: &getblock.Response{Result: json.RawMessage(`"0X1"`)}},
+		{name: "late failure", lateErr: errors.New("connection reset")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			reported := make(chan *getblock.Disagreement, 1)
+			done := make(chan struct{})
+
+			q := &getblock.Quorum{
+				Clients: []getblock.Caller{
+					answer(`"0x1"`),
+					answer(`"0x1"`),
+					callerFunc(func(ctx context.Context) (*getblock.Response, error) {
+						defer close(done)
+						<-release
+						// late call is not canceled once quorum is reached
+						if err := ctx.Err(); err != nil {
+							return nil, err
+						}
+
+						return tt.late, tt.lateErr
+					}),
+				},
+				Threshold: 2,
+				OnDisagreement: func(d *getblock.Disagreement) {
+					reported <- d
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			r, err := q.Call(ctx, "eth_blockNumber")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(r.Result) != `"0x1"` {
+				t.Errorf("result = %s, want \"0x1\"", r.Result)
+			}
+
+			cancel()
+			close(release)
+			<-done
+
+			select {
+			case d := <-reported:
+				if !tt.disagree {
+					t.Fatalf("unexpected disagreement: %+v", d.Answers)
+				}
+
+				if d.Method != "eth_blockNumber" || len(d.Answers) != 3 {
+					t.Errorf("disagreement = %+v, want 3 answers to eth_blockNumber", d)
+				}
+
+				for _, a := range d.Answers {
+					if a.Client == 2 && a.Err != nil {
+						t.Errorf("late answer error = %v", a.Err)
+					}
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.disagree {
+					t.Error("disagreement is not reported")
+				}
+			}
+		})
+	}
+}
+
+func TestQuorumNotReached(t *testing.T) {
+	var reported bool
+	q := &getblock.Quorum{
+		Clients: []getblock.Caller{
+			answer(`"0x1"`),
+			answer(`"0x2"`),
+			callerFunc(func(ctx context.Context) (*getblock.Response, error) {
+				return nil, errors.New("connection reset")
+			}),
+		},
+		Threshold:      2,
+		OnDisagreement: func(*getblock.Disagreement) { reported = true },
+	}
+
+	_, err := q.Call(context.Background(), "eth_blockNumber")
+
+	var quorumErr *getblock.QuorumError
+	if !errors.As(err, &quorumErr) {
+		t.Fatalf("error = %v, want QuorumError", err)
+	}
+
+	if quorumErr.Threshold != 2 || quorumErr.Method != "eth_blockNumber" || len(quorumErr.Answers) != 3 {
+		t.Errorf("error = %+v", quorumErr)
+	}
+
+	if reported {
+		t.Error("OnDisagreement is called without quorum")
+	}
+}
+
+func TestQuorumCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &getblock.Quorum{
+		Clients: []getblock.Caller{
+			answer(`"0x1"`),
+			callerFunc(func(ctx context.Context) (*getblock.Response, error) {
+				cancel()
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}),
+		},
+		Threshold: 2,
+	}
+
+	if _, err := q.Call(ctx, "eth_blockNumber"); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestQuorumNotRead(t *testing.T) {
+	q := &getblock.Quorum{Clients: []getblock.Caller{answer(`"0x1"`)}, Threshold: 1}
+	if _, err := q.Call(context.Background(), "eth_sendRawTransaction", "0x00"); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,116 @@
+package getblock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit limits rate of requests sent by Client to rps requests per second
+// with bursts of at most burst requests. Every attempt of a call is counted.
+// Rate is halved on every 429 response and gradually restored on successful responses.
+// Non-positive rps disables rate limiting.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.limiter = nil
+			return
+		}
+
+		c.limiter = newRateLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// WithMethodWeights sets number of requests counted by rate limiter per call of method.
+// Methods without weight are counted as single request. Weights are capped by burst.
+func WithMethodWeights(weights map[string]int) Option {
+	return func(c *Client) {
+		c.weights = weights
+	}
+}
+
+type rateLimiter struct {
+	limiter *rate.Limiter
+	limit   rate.Limit
+
+	mu      sync.Mutex
+	current rate.Limit
+}
+
+func newRateLimiter(limit rate.Limit, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		limiter: rate.NewLimiter(limit, burst),
+		limit:   limit,
+		current: limit,
+	}
+}
+
+// middleware waits for rate limiter before every request and adapts rate to responses.
+func (l *rateLimiter) middleware(weights map[string]int) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			if err := l.wait(ctx, weights[req.Method]); err != nil {
+				return nil, err
+			}
+
+			r, err := next.Handle(ctx, req)
+			l.observe(err)
+
+			return r, err
+		})
+	}
+}
+
+// wait blocks until n requests are allowed or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if n < 1 {
+		n = 1
+	}
+
+	if b := l.limiter.Burst(); n > b {
+		n = b
+	}
+
+	if err := l.limiter.WaitN(ctx, n); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return fmt.Errorf("getblock: rate limit: %v: %w", err, context.DeadlineExceeded)
+	}
+
+	return nil
+}
+
+// observe slows rate down on 429 response and restores it on success.
+func (l *rateLimiter) observe(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.current
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code == http.StatusTooManyRequests {
+		current /= 2
+		if floor := l.limit / 16; current < floor {
+			current = floor
+		}
+	} else if err == nil && current < l.limit {
+		current += l.limit / 50
+		if current > l.limit {
+			current = l.limit
+		}
+	}
+
+	if current != l.current {
+		l.current = current
+		l.limiter.SetLimit(current)
+	}
+}
@@ -0,0 +1,101 @@
+package getblock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	l := newRateLimiter(100, 2)
+
+	// burst is available immediately
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := l.wait(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d > 10*time.Millisecond {
+		t.Errorf("burst took %v", d)
+	}
+
+	// then tokens are refilled at 100 per second, weight is capped by burst
+	start = time.Now()
+	if err := l.wait(context.Background(), 10); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 15*time.Millisecond {
+		t.Errorf("waited %v for 2 tokens, want at least 20ms", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	tooMany := &HTTPError{Code: http.StatusTooManyRequests}
+
+	tests := []struct {
+		name string
+		errs []error
+		want rate.Limit
+	}{
+		{name: "success at full rate", errs: []error{nil}, want: 160},
+		{name: "429 halves rate", errs: []error{tooMany}, want: 80},
+		{name: "wrapped 429 halves rate", errs: []error{fmt.Errorf("attempt 1: %w", tooMany)}, want: 80},
+		{name: "rate has floor", errs: []error{tooMany, tooMany, tooMany, tooMany, tooMany, tooMany}, want: 10},
+		{name: "other errors keep rate", errs: []error{tooMany, &HTTPError{Code: http.StatusBadGateway}, errors.New("boom")}, want: 80},
+		{name: "success restores rate", errs: []error{tooMany, nil, nil}, want: 80 + 2*3.2},
+		{name: "rate is restored up to limit", errs: append([]error{tooMany}, make([]error, 100)...), want: 160},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(160, 1)
+			for _, err := range tt.errs {
+				l.observe(err)
+			}
+
+			if got := l.limiter.Limit(); math.Abs(float64(got-tt.want)) > 1e-9 {
+				t.Errorf("limit = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	l := newRateLimiter(1000, 10)
+
+	var calls int
+	h := l.middleware(map[string]int{"trace_block": 4})(HandlerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+		calls++
+		return nil, fmt.Errorf("send: %w", &HTTPError{Code: http.StatusTooManyRequests})
+	}))
+
+	if _, err := h.Handle(context.Background(), &Request{Method: "trace_block"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+
+	// weighted call took 4 tokens of burst
+	if got := l.limiter.Tokens(); got > 6.5 {
+		t.Errorf("tokens = %v, want about 6", got)
+	}
+
+	if got := l.limiter.Limit(); got != 500 {
+		t.Errorf("limit = %v, want 500", got)
+	}
+}
@@ -0,0 +1,44 @@
+package getblock
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redacted replaces secrets in strings exposed to logs and telemetry.
+const redacted = "***"
+
+// newRedactor creates replacer of tokens and endpoint URLs which may embed tokens.
+// Endpoint URL is replaced with its scheme and host. Endpoints without URL (e.g. with Conn only)
+// contribute only their token.
+func newRedactor(endpoints []Endpoint) *strings.Replacer {
+	var oldnew []string
+	for _, e := range endpoints {
+		if e.URL != "" {
+			if u, err := url.Parse(e.URL); err == nil {
+				oldnew = append(oldnew, u.String(), redactURL(u))
+				if u.User != nil && u.User.String() != "" {
+					oldnew = append(oldnew, u.User.String(), redacted)
+				}
+			}
+
+			oldnew = append(oldnew, e.URL, redacted)
+		}
+
+		if e.Token != "" {
+			oldnew = append(oldnew, e.Token, redacted)
+		}
+	}
+
+	return strings.NewReplacer(oldnew...)
+}
+
+// redact replaces tokens and endpoint URLs in s.
+func (c *Client) redact(s string) string {
+	return c.redactor.Replace(s)
+}
+
+// redactURL returns scheme and host of u with path replaced, as path may embed token.
+func redactURL(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + "/" + redacted
+}
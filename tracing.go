@@ -0,0 +1,76 @@
+package getblock
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is name of instrumentation library used for telemetry.
+const instrumentationName = "github.com/ofen/getblock-go"
+
+// WithTracerProvider enables OpenTelemetry tracing creating client span per call.
+// Span is child of span from call context and records method, endpoint host, number of retries,
+// HTTP status and JSON-RPC error code. Tokens are never recorded.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(instrumentationName)
+	}
+}
+
+func (c *Client) tracingMiddleware(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+		ctx, span := c.tracer.Start(ctx, req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "jsonrpc"),
+				attribute.String("rpc.method", req.Method),
+				attribute.String("rpc.jsonrpc.version", "2.0"),
+			),
+		)
+		defer span.End()
+
+		r, err := next.Handle(ctx, req)
+
+		if info := callInfoFromContext(ctx); info != nil {
+			s := info.snapshot()
+			retries := s.attempts - 1
+			if retries < 0 {
+				retries = 0
+			}
+
+			span.SetAttributes(attribute.Int("getblock.retry_count", retries))
+			if s.hedges > 0 {
+				span.SetAttributes(attribute.Int("getblock.hedge_count", s.hedges))
+			}
+
+			if s.host != "" {
+				span.SetAttributes(attribute.String("server.address", s.host))
+			}
+
+			if s.status != 0 {
+				span.SetAttributes(attribute.Int("http.response.status_code", s.status))
+			}
+		}
+
+		if r != nil && r.Error != nil {
+			span.SetAttributes(
+				attribute.Int("rpc.jsonrpc.error_code", r.Error.Code),
+				attribute.String("rpc.jsonrpc.error_message", c.redact(r.Error.Message)),
+			)
+			span.SetStatus(codes.Error, c.redact(r.Error.Message))
+		}
+
+		if err != nil {
+			msg := c.redact(err.Error())
+			span.AddEvent("exception", trace.WithAttributes(
+				attribute.String("exception.message", msg),
+			))
+			span.SetStatus(codes.Error, msg)
+		}
+
+		return r, err
+	})
+}
@@ -0,0 +1,151 @@
+package getblock_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestTracing(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		setup  func(s *getblocktest.Server)
+		want   map[attribute.Key]attribute.Value
+		status codes.Code
+	}{
+		{
+			name:   "ok",
+			method: "eth_blockNumber",
+			setup: func(s *getblocktest.Server) {
+				s.Respond("eth_blockNumber", "0x1")
+			},
+			want: map[attribute.Key]attribute.Value{
+				"getblock.retry_count":      attribute.IntValue(0),
+				"http.response.status_code": attribute.IntValue(http.StatusOK),
+			},
+			status: codes.Unset,
+		},
+		{
+			name:   "retry after 5xx",
+			method: "eth_blockNumber",
+			setup: func(s *getblocktest.Server) {
+				s.Respond("eth_blockNumber", "0x1")
+				s.Inject(getblocktest.Fault{Times: 1, Status: http.StatusBadGateway})
+			},
+			want: map[attribute.Key]attribute.Value{
+				"getblock.retry_count":      attribute.IntValue(1),
+				"http.response.status_code": attribute.IntValue(http.StatusOK),
+			},
+			status: codes.Unset,
+		},
+		{
+			name:   "rpc error",
+			method: "eth_call",
+			setup: func(s *getblocktest.Server) {
+				s.RespondError("eth_call", getblocktest.CodeServerError, "execution reverted")
+			},
+			want: map[attribute.Key]attribute.Value{
+				"getblock.retry_count":      attribute.IntValue(0),
+				"http.response.status_code": attribute.IntValue(http.StatusOK),
+				"rpc.jsonrpc.error_code":    attribute.IntValue(getblocktest.CodeServerError),
+			},
+			status: codes.Error,
+		},
+		{
+			name:   "http error",
+			method: "eth_blockNumber",
+			setup: func(s *getblocktest.Server) {
+				s.Inject(getblocktest.Fault{Status: http.StatusTooManyRequests})
+			},
+			want: map[attribute.Key]attribute.Value{
+				"getblock.retry_count":      attribute.IntValue(0),
+				"http.response.status_code": attribute.IntValue(http.StatusTooManyRequests),
+			},
+			status: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			tt.setup(s)
+
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			defer tp.Shutdown(context.Background())
+
+			// token is part of URL path as in GetBlock endpoints
+			c := getblock.New("", s.URL+"/"+getblocktest.Token+"/", getblock.WithTracerProvider(tp))
+
+			ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+			c.Call(ctx, tt.method)
+			parent.End()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 2 {
+				t.Fatalf("got %d spans, want 2", len(spans))
+			}
+
+			span := spans[0]
+			if span.Name != tt.method {
+				t.Errorf("name = %q, want %q", span.Name, tt.method)
+			}
+
+			if span.SpanKind != trace.SpanKindClient {
+				t.Errorf("kind = %v, want %v", span.SpanKind, trace.SpanKindClient)
+			}
+
+			if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("span is not child of caller span")
+			}
+
+			if span.Status.Code != tt.status {
+				t.Errorf("status = %v, want %v", span.Status.Code, tt.status)
+			}
+
+			u, _ := url.Parse(s.URL)
+			want := map[attribute.Key]attribute.Value{
+				"rpc.method":     attribute.StringValue(tt.method),
+				"server.address": attribute.StringValue(u.Host),
+			}
+			for k, v := range tt.want {
+				want[k] = v
+			}
+
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, kv := range span.Attributes {
+				attrs[kv.Key] = kv.Value
+				if strings.Contains(kv.Value.Emit(), getblocktest.Token) {
+					t.Errorf("attribute %s contains token: %s", kv.Key, kv.Value.Emit())
+				}
+			}
+
+			for k, v := range want {
+				if attrs[k] != v {
+					t.Errorf("%s = %v, want %v", k, attrs[k].Emit(), v.Emit())
+				}
+			}
+
+			for _, e := range span.Events {
+				for _, kv := range e.Attributes {
+					if strings.Contains(kv.Value.Emit(), getblocktest.Token) {
+						t.Errorf("event %s attribute %s contains token", e.Name, kv.Key)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package getblock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Response is JSON-RPC response. Result is kept undecoded until Decode is called,
+// so it is decoded once, directly into target type.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// Decode decodes result into v. Null or missing result sets v to its zero value
+// if v is pointer to pointer, slice or map, like encoding/json does.
+func (r *Response) Decode(v interface{}) error {
+	if len(r.Result) == 0 {
+		return json.Unmarshal([]byte("null"), v)
+	}
+
+	return json.Unmarshal(r.Result, v)
+}
+
+// RPCError is JSON-RPC error object.
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return strconv.Itoa(e.Code) + ": " + e.Message
+}
+
+// HTTPError is returned when endpoint responds with HTTP status 400 or above.
+// If body of such response is JSON-RPC response, it is returned along with error.
+type HTTPError struct {
+	Code int
+	// Body is response body.
+	Body []byte
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("getblock: unexpected HTTP status %d %s", e.Code, http.StatusText(e.Code))
+
+	var r struct {
+		Error *RPCError `json:"error"`
+	}
+	if json.Unmarshal(e.Body, &r) == nil && r.Error != nil {
+		msg += ": " + r.Error.Error()
+	}
+
+	return msg
+}
+
+// Conn is connection to JSON-RPC endpoint, e.g. HTTP or WebSocket.
+// Implementations must be safe for concurrent use.
+type Conn interface {
+	// RoundTrip sends encoded single or batch request and returns encoded response.
+	// Error returned along with response body should be *HTTPError.
+	RoundTrip(ctx context.Context, request []byte) ([]byte, error)
+}
+
+// maxPrealloc is maximum size of response body buffer allocated before reading body.
+const maxPrealloc = 16 << 20
+
+// httpConn is Conn sending requests with HTTP POST.
+type httpConn struct {
+	url string
+	// safeURL is url with token redacted, used in errors.
+	safeURL string
+	token   string
+	client  *http.Client
+}
+
+func (c *httpConn) RoundTrip(ctx context.Context, request []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(request))
+	if err != nil {
+		return nil, c.redactErr(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set(authorizationHeaderKey, c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, c.redactErr(err)
+	}
+	defer resp.Body.Close()
+
+	// Content-Length is not trusted beyond maxPrealloc, larger bodies grow buffer as they are read
+	buf := &bytes.Buffer{}
+	if resp.ContentLength > 0 && resp.ContentLength <= maxPrealloc {
+		buf.Grow(int(resp.ContentLength))
+	}
+
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, c.redactErr(err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return buf.Bytes(), &HTTPError{Code: resp.StatusCode, Body: buf.Bytes()}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// redactErr replaces URL in url.Error, as it may embed token.
+func (c *httpConn) redactErr(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return &url.Error{Op: urlErr.Op, URL: c.safeURL, Err: urlErr.Err}
+	}
+
+	return err
+}
+
+// request is encoded JSON-RPC request.
+type request struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+func newRequest(id uint64, method string, params []interface{}) request {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	return request{JSONRPC: "2.0", ID: id, Method: method, Params: params}
+}
+
+// call sends encoded request to endpoint and decodes response into v.
+// If response has HTTP error status, v is decoded if possible and HTTPError is returned.
+func (e *endpoint) call(ctx context.Context, request []byte, v interface{}) error {
+	data, err := e.conn.RoundTrip(ctx, request)
+	var httpErr *HTTPError
+	if err != nil && (!errors.As(err, &httpErr) || len(data) == 0) {
+		return err
+	}
+
+	if decodeErr := json.Unmarshal(data, v); decodeErr != nil && err == nil {
+		return fmt.Errorf("%w: %w", ErrInvalidResponse, decodeErr)
+	}
+
+	return err
+}
+
+// isNull reports whether result is null or missing.
+func isNull(result json.RawMessage) bool {
+	return len(result) == 0 || string(result) == "null"
+}
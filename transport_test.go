@@ -0,0 +1,105 @@
+package getblock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestTransportErrorRedacted(t *testing.T) {
+	s := getblocktest.NewServer()
+	addr := s.URL
+	s.Close()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "connection refused", url: addr + "/" + getblocktest.Token + "/"},
+		{name: "invalid URL", url: "http://[::1" + getblocktest.Token},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := getblock.New("", tt.url, getblock.WithRetry(1))
+
+			_, err := c.Call(context.Background(), "eth_blockNumber")
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			if strings.Contains(err.Error(), getblocktest.Token) {
+				t.Errorf("error contains token: %v", err)
+			}
+
+			var urlErr *url.Error
+			if !errors.As(err, &urlErr) {
+				t.Errorf("error is not url.Error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransportContentLengthNotTrusted(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		body := `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
+		fmt.Fprintf(buf, "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s", int64(1)<<40, body)
+		buf.Flush()
+	}))
+	defer s.Close()
+
+	c := getblock.New("", s.URL, getblock.WithRetry(1))
+	if _, err := c.Call(context.Background(), "eth_blockNumber"); err == nil {
+		t.Fatal("expected error of truncated body")
+	}
+}
+
+// roundTripperFunc is http.RoundTripper calling function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// errReader fails every read with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestTransportBodyErrorRedacted(t *testing.T) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := errReader{&url.Error{Op: "read", URL: req.URL.String(), Err: io.ErrUnexpectedEOF}}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Request: req}, nil
+	})
+
+	c := getblock.New("", "https://go.getblock.io/"+getblocktest.Token+"/",
+		getblock.WithRetry(1), getblock.WithHTTPClient(&http.Client{Transport: transport}))
+
+	_, err := c.Call(context.Background(), "eth_blockNumber")
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("error = %v, want unexpected EOF", err)
+	}
+
+	if strings.Contains(err.Error(), getblocktest.Token) {
+		t.Errorf("error contains token: %v", err)
+	}
+}
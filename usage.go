@@ -0,0 +1,149 @@
+package getblock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithComputeUnits sets cost of methods in compute units used to estimate usage.
+// Methods missing from costs are charged defaultCost. By default every request costs 1 unit.
+func WithComputeUnits(costs map[string]int64, defaultCost int64) Option {
+	return func(c *Client) {
+		c.usage.costs = costs
+		c.usage.defaultCost = defaultCost
+	}
+}
+
+// WithDailyBudget makes Client refuse calls with QuotaExceededError once estimated
+// compute units spent since start of current UTC day would exceed budget.
+func WithDailyBudget(budget int64) Option {
+	return func(c *Client) {
+		c.usage.budget = budget
+	}
+}
+
+// QuotaExceededError is returned when daily compute units budget is exhausted.
+type QuotaExceededError struct {
+	Method string
+	Budget int64
+	Used   int64
+	// Reset is time when budget is renewed.
+	Reset time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("getblock: %s: daily budget of %d compute units exhausted (%d used), resets at %s", e.Method, e.Budget, e.Used, e.Reset.Format(time.RFC3339))
+}
+
+// Usage is snapshot of requests sent by Client. Every attempt of a call is counted,
+// attempts failed before being sent (e.g. with ErrNoEndpoints or CircuitOpenError) are not.
+type Usage struct {
+	Methods map[string]MethodUsage
+	// ComputeUnits is estimated compute units spent since Client creation.
+	ComputeUnits int64
+	// DailyComputeUnits is estimated compute units spent since Day.
+	DailyComputeUnits int64
+	// Day is start of current UTC day.
+	Day time.Time
+}
+
+// MethodUsage is usage of single method.
+type MethodUsage struct {
+	Requests     int64
+	ComputeUnits int64
+}
+
+// Usage returns snapshot of requests sent by Client.
+func (c *Client) Usage() Usage {
+	return c.usage.snapshot()
+}
+
+type usageTracker struct {
+	costs       map[string]int64
+	defaultCost int64
+	budget      int64
+
+	mu      sync.Mutex
+	methods map[string]MethodUsage
+	total   int64
+	daily   int64
+	day     time.Time
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{
+		defaultCost: 1,
+		methods:     make(map[string]MethodUsage),
+	}
+}
+
+func (u *usageTracker) cost(method string) int64 {
+	if cost, ok := u.costs[method]; ok {
+		return cost
+	}
+
+	return u.defaultCost
+}
+
+// charge accounts request of methods sent upstream, several methods are charged for batch.
+// It fails without charging anything if request does not fit into daily budget.
+func (u *usageTracker) charge(methods ...string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.rollover()
+	if u.budget > 0 {
+		daily := u.daily
+		for _, method := range methods {
+			daily += u.cost(method)
+			if daily > u.budget {
+				return &QuotaExceededError{
+					Method: method,
+					Budget: u.budget,
+					Used:   u.daily,
+					Reset:  u.day.AddDate(0, 0, 1),
+				}
+			}
+		}
+	}
+
+	for _, method := range methods {
+		cost := u.cost(method)
+		m := u.methods[method]
+		m.Requests++
+		m.ComputeUnits += cost
+		u.methods[method] = m
+		u.total += cost
+		u.daily += cost
+	}
+
+	return nil
+}
+
+func (u *usageTracker) snapshot() Usage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.rollover()
+	methods := make(map[string]MethodUsage, len(u.methods))
+	for k, v := range u.methods {
+		methods[k] = v
+	}
+
+	return Usage{
+		Methods:           methods,
+		ComputeUnits:      u.total,
+		DailyComputeUnits: u.daily,
+		Day:               u.day,
+	}
+}
+
+// rollover resets daily counter on start of new UTC day.
+func (u *usageTracker) rollover() {
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	if !day.Equal(u.day) {
+		u.day = day
+		u.daily = 0
+	}
+}
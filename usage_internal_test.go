@@ -0,0 +1,36 @@
+package getblock
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUsageRollover(t *testing.T) {
+	u := newUsageTracker()
+	u.budget = 2
+
+	if err := u.charge("eth_blockNumber", "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+
+	var quotaErr *QuotaExceededError
+	if err := u.charge("eth_blockNumber"); !errors.As(err, &quotaErr) {
+		t.Fatalf("error = %v, want QuotaExceededError", err)
+	}
+
+	// budget is renewed on start of new UTC day, total usage is kept
+	u.day = u.day.AddDate(0, 0, -1)
+	if err := u.charge("eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+
+	usage := u.snapshot()
+	if usage.DailyComputeUnits != 1 || usage.ComputeUnits != 3 || usage.Methods["eth_blockNumber"].Requests != 3 {
+		t.Errorf("usage = %+v, want 1 daily and 3 total compute units", usage)
+	}
+
+	if want := time.Now().UTC().Truncate(24 * time.Hour); !usage.Day.Equal(want) {
+		t.Errorf("day = %v, want %v", usage.Day, want)
+	}
+}
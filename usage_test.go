@@ -0,0 +1,160 @@
+package getblock_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ofen/getblock-go"
+	"github.com/ofen/getblock-go/getblocktest"
+)
+
+func TestUsage(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []getblock.Option
+		calls []string
+		// fail makes first request fail with 502 and be retried.
+		fail        bool
+		wantMethods map[string]getblock.MethodUsage
+		wantTotal   int64
+	}{
+		{
+			name:  "request costs 1 unit by default",
+			calls: []string{"eth_blockNumber", "eth_getLogs", "eth_getLogs"},
+			wantMethods: map[string]getblock.MethodUsage{
+				"eth_blockNumber": {Requests: 1, ComputeUnits: 1},
+				"eth_getLogs":     {Requests: 2, ComputeUnits: 2},
+			},
+			wantTotal: 3,
+		},
+		{
+			name:  "cost table and default cost",
+			opts:  []getblock.Option{getblock.WithComputeUnits(map[string]int64{"eth_getLogs": 75, "eth_call": 20}, 5)},
+			calls: []string{"eth_blockNumber", "eth_getLogs", "eth_getLogs", "eth_call"},
+			wantMethods: map[string]getblock.MethodUsage{
+				"eth_blockNumber": {Requests: 1, ComputeUnits: 5},
+				"eth_getLogs":     {Requests: 2, ComputeUnits: 150},
+				"eth_call":        {Requests: 1, ComputeUnits: 20},
+			},
+			wantTotal: 175,
+		},
+		{
+			name:  "every attempt is charged",
+			opts:  []getblock.Option{getblock.WithRetry(2), getblock.WithComputeUnits(map[string]int64{"eth_getLogs": 75}, 1)},
+			calls: []string{"eth_getLogs"},
+			fail:  true,
+			wantMethods: map[string]getblock.MethodUsage{
+				"eth_getLogs": {Requests: 2, ComputeUnits: 150},
+			},
+			wantTotal: 150,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := getblocktest.NewServer()
+			defer s.Close()
+			if tt.fail {
+				s.Inject(getblocktest.Fault{Times: 1, Status: http.StatusBadGateway})
+			}
+
+			c := s.GetblockClient(tt.opts...)
+			for _, method := range tt.calls {
+				s.Respond(method, "0x1")
+				if _, err := getblock.CallFor[string](context.Background(), c, method); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			usage := c.Usage()
+			if !reflect.DeepEqual(usage.Methods, tt.wantMethods) {
+				t.Errorf("methods = %v, want %v", usage.Methods, tt.wantMethods)
+			}
+
+			if usage.ComputeUnits != tt.wantTotal || usage.DailyComputeUnits != tt.wantTotal {
+				t.Errorf("compute units = %d, daily %d, want %d", usage.ComputeUnits, usage.DailyComputeUnits, tt.wantTotal)
+			}
+
+			if want := time.Now().UTC().Truncate(24 * time.Hour); !usage.Day.Equal(want) {
+				t.Errorf("day = %v, want %v", usage.Day, want)
+			}
+		})
+	}
+}
+
+func TestDailyBudget(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Respond("eth_getLogs", []interface{}{})
+	s.Respond("eth_blockNumber", "0x1")
+
+	c := s.GetblockClient(getblock.WithDailyBudget(50), getblock.WithComputeUnits(map[string]int64{"eth_getLogs": 30}, 1))
+	ctx := context.Background()
+
+	if _, err := c.Call(ctx, "eth_getLogs"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.Call(ctx, "eth_getLogs")
+
+	var quotaErr *getblock.QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("error = %v, want QuotaExceededError", err)
+	}
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	want := &getblock.QuotaExceededError{Method: "eth_getLogs", Budget: 50, Used: 30, Reset: day.AddDate(0, 0, 1)}
+	if !reflect.DeepEqual(quotaErr, want) {
+		t.Errorf("error = %+v, want %+v", quotaErr, want)
+	}
+
+	if calls := s.Calls("eth_getLogs"); len(calls) != 1 {
+		t.Errorf("got %d eth_getLogs requests, want 1", len(calls))
+	}
+
+	// cheaper calls still fit into budget
+	if _, err := c.Call(ctx, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+
+	// batch which does not fit is not charged at all
+	err = c.CallBatch(ctx, []getblock.BatchElem{{Method: "eth_blockNumber"}, {Method: "eth_getLogs"}})
+	if !errors.As(err, &quotaErr) || quotaErr.Method != "eth_getLogs" {
+		t.Fatalf("error = %v, want QuotaExceededError of eth_getLogs", err)
+	}
+
+	if usage := c.Usage(); usage.DailyComputeUnits != 31 || usage.Methods["eth_blockNumber"].Requests != 1 {
+		t.Errorf("usage = %+v, want 31 compute units", usage)
+	}
+}
+
+func TestUsageNotSent(t *testing.T) {
+	s := getblocktest.NewServer()
+	defer s.Close()
+	s.Inject(getblocktest.Fault{Status: http.StatusBadGateway})
+
+	c := s.GetblockClient(getblock.WithRetry(3), getblock.WithCircuitBreaker(getblock.CircuitBreaker{Failures: 1, Cooldown: time.Hour}))
+	ctx := context.Background()
+
+	// first attempt is sent and opens circuit, retries are rejected by open circuit
+	var circuitErr *getblock.CircuitOpenError
+	if _, err := c.Call(ctx, "eth_blockNumber"); !errors.As(err, &circuitErr) {
+		t.Fatalf("error = %v, want CircuitOpenError", err)
+	}
+
+	if _, err := c.Call(ctx, "eth_blockNumber"); !errors.As(err, &circuitErr) {
+		t.Fatalf("error = %v, want CircuitOpenError", err)
+	}
+
+	if _, err := c.Call(getblock.WithEndpoint(ctx, "http://127.0.0.1:1/"), "eth_blockNumber"); !errors.Is(err, getblock.ErrNoEndpoints) {
+		t.Fatalf("error = %v, want ErrNoEndpoints", err)
+	}
+
+	if usage := c.Usage(); usage.ComputeUnits != 1 || usage.Methods["eth_blockNumber"].Requests != 1 {
+		t.Errorf("usage = %+v, want single request", usage)
+	}
+}